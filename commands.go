@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"EffectiveMobile/internal/config"
+	"EffectiveMobile/internal/migrate"
+)
+
+const usage = `usage:
+  main                         start the web server
+  main migrate up              apply pending migrations
+  main migrate down [steps]    revert the last steps migrations (default 1)
+  main migrate status          show migration status
+  main config print            print the effective configuration with secrets redacted
+
+flags before the command override the environment, e.g. main -webserver-port 9090;
+-config sets a YAML or .env configuration file (default .env if present)`
+
+// runCommand : Выполнение команды командной строки вместо запуска веб сервера
+func runCommand(migrator *migrate.Migrator, args []string) error {
+	ctx := context.Background()
+
+	if len(args) < 2 || args[0] != "migrate" {
+		return fmt.Errorf("unknown command %q\n%v", strings.Join(args, " "), usage)
+	}
+
+	switch args[1] {
+	case "up":
+		count, err := migrator.Up(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Applied %v migrations\n", count)
+	case "down":
+		steps := 1
+		if len(args) > 2 {
+			parsed, err := strconv.Atoi(args[2])
+			if err != nil || parsed < 1 {
+				return fmt.Errorf("steps must be a positive number\n%v", usage)
+			}
+			steps = parsed
+		}
+		count, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Reverted %v migrations\n", count)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED AT")
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%06d\t%v\t%v\n", status.Version, status.Name, appliedAt)
+		}
+		if err = w.Flush(); err != nil {
+			return err
+		}
+		fmt.Printf("Application schema version: %v\n", migrator.Latest())
+	default:
+		return fmt.Errorf("unknown migrate command %q\n%v", args[1], usage)
+	}
+	return nil
+}
+
+// runConfigCommand : Выполнение команды config, не требующей подключения к базе данных
+func runConfigCommand(conf config.Config, args []string) error {
+	if len(args) != 2 || args[1] != "print" {
+		return fmt.Errorf("unknown command %q\n%v", strings.Join(args, " "), usage)
+	}
+	return conf.Print(os.Stdout)
+}
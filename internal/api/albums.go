@@ -0,0 +1,281 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/a-h/respond"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"EffectiveMobile/internal/models"
+)
+
+// decodeAlbum : Разбор и проверка тела запроса с альбомом
+func decodeAlbum(body io.Reader) (album models.AlbumRequest, err error) {
+	if err = json.NewDecoder(body).Decode(&album); err != nil {
+		return album, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err)
+	}
+
+	album.Title = strings.TrimSpace(album.Title)
+	if album.Title == "" || album.ArtistID == "" {
+		return album, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Album title, artistId are required", nil)
+	}
+	if utf8.RuneCountInString(album.Title) > maxFieldLength || utf8.RuneCountInString(album.CoverLink) > maxFieldLength {
+		return album, models.NewError(models.ErrBadRequest, models.CodeInvalidValue, fmt.Sprintf("Album title and cover link must be at most %v characters", maxFieldLength), nil)
+	}
+	if err = validateRelease(album.Release); err != nil {
+		return album, err
+	}
+	if err = validateLink(album.CoverLink); err != nil {
+		return album, err
+	}
+	if err = validateTracks(album.Tracks); err != nil {
+		return album, err
+	}
+	return album, nil
+}
+
+// validateTracks : Проверка списка ID песен треков, песня может встречаться в альбоме только один раз
+func validateTracks(tracks []string) error {
+	seen := make(map[string]bool, len(tracks))
+	for _, track := range tracks {
+		if track == "" {
+			return models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Track song ID is required", nil)
+		}
+		if seen[track] {
+			return models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, fmt.Sprintf("Song %v is listed more than once", track), nil)
+		}
+		seen[track] = true
+	}
+	return nil
+}
+
+// createAlbum : Обработка запроса для создания альбома
+func (h *ApiHandler) createAlbum(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("CreateAlbum handler")
+
+	var result models.Album
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	album, err := decodeAlbum(request.Body)
+	if err != nil {
+		h.loger.Errorf("Error decoding album: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.CreateAlbum(request.Context(), album)
+	if err != nil {
+		h.loger.Errorf("Error creating album: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusCreated)
+}
+
+// readAlbum : Обработка запроса для получения альбома с треками по его ID
+func (h *ApiHandler) readAlbum(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("ReadAlbum handler")
+
+	var result models.Album
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	result, err := h.service.GetAlbum(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error getting album: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// getAlbumsList : Обработка запроса для получения списка альбомов
+func (h *ApiHandler) getAlbumsList(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetAlbumsList handler")
+
+	var result models.AlbumsListResponse
+	var paginationOptions models.PaginationOptions
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	if options, ok := request.Context().Value("pagination_options").(models.PaginationOptions); ok {
+		paginationOptions = options
+	}
+	// Список альбомов поддерживает только смещение
+	if paginationOptions.Offset == "" {
+		paginationOptions.Offset = defaultOffset
+	}
+
+	result, err := h.service.GetAlbumsList(request.Context(), paginationOptions)
+	if err != nil {
+		h.loger.Errorf("Error getting albums list: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// updateAlbum : Обработка запроса для обновления альбома
+func (h *ApiHandler) updateAlbum(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("UpdateAlbum handler")
+
+	var result models.Album
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	album, err := decodeAlbum(request.Body)
+	if err != nil {
+		h.loger.Errorf("Error decoding album: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.UpdateAlbum(request.Context(), guid, album)
+	if err != nil {
+		h.loger.Errorf("Error updating album: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// deleteAlbum : Обработка запроса для удаления альбома
+func (h *ApiHandler) deleteAlbum(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("DeleteAlbum handler")
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	err := h.service.DeleteAlbum(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error deleting album: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, models.AlbumResponse{ID: guid}, http.StatusOK)
+}
+
+// setAlbumTracks : Обработка запроса для замены и перестановки треков альбома
+func (h *ApiHandler) setAlbumTracks(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("SetAlbumTracks handler")
+
+	var tracks models.AlbumTracksRequest
+	var result models.Album
+	var err error
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	if err = json.NewDecoder(request.Body).Decode(&tracks); err != nil {
+		h.loger.Errorf("Error unmarshalling request body: %v", err)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err), reqID)
+		return
+	}
+
+	if err = validateTracks(tracks.Tracks); err != nil {
+		h.loger.Errorf("Error validating tracks: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.SetAlbumTracks(request.Context(), guid, tracks.Tracks)
+	if err != nil {
+		h.loger.Errorf("Error setting album tracks: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// addAlbumTrack : Обработка запроса для добавления песни в конец альбома
+func (h *ApiHandler) addAlbumTrack(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("AddAlbumTrack handler")
+
+	var track models.AlbumTrackRequest
+	var result models.Album
+	var err error
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	if err = json.NewDecoder(request.Body).Decode(&track); err != nil {
+		h.loger.Errorf("Error unmarshalling request body: %v", err)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err), reqID)
+		return
+	}
+
+	if track.SongID == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Track song ID is required", nil), reqID)
+		return
+	}
+
+	result, err = h.service.AddAlbumTrack(request.Context(), guid, track.SongID)
+	if err != nil {
+		h.loger.Errorf("Error adding album track: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusCreated)
+}
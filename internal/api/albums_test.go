@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestDecodeAlbum(t *testing.T) {
+	album, err := decodeAlbum(strings.NewReader(`{"title": " Absolution ", "artistId": "a", "releaseDate": "15.09.2003", "tracks": ["1", "2"]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Absolution", album.Title)
+	assert.Equal(t, []string{"1", "2"}, album.Tracks)
+
+	// Без поля tracks треки альбома не меняются
+	album, err = decodeAlbum(strings.NewReader(`{"title": "Absolution", "artistId": "a"}`))
+	require.NoError(t, err)
+	assert.Nil(t, album.Tracks)
+
+	for body, kind := range map[string]error{
+		`{"artistId": "a"}`:       models.ErrBadRequest,
+		`{"title": "Absolution"}`: models.ErrBadRequest,
+		`{"title": "Absolution", "artistId": "a", "releaseDate": "2003-09-15"}`: models.ErrValidation,
+		`{"title": "Absolution", "artistId": "a", "coverLink": "cover.png"}`:    models.ErrValidation,
+		`{"title": "Absolution", "artistId": "a", "tracks": ["1", "1"]}`:        models.ErrBadRequest,
+		`{"title": "Absolution", "artistId": "a", "tracks": [""]}`:              models.ErrBadRequest,
+	} {
+		_, err = decodeAlbum(strings.NewReader(body))
+		var domainErr *models.DomainError
+		require.True(t, errors.As(err, &domainErr), body)
+		assert.Equal(t, kind, domainErr.Kind, body)
+	}
+}
@@ -0,0 +1,157 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/a-h/respond"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/models"
+)
+
+// decodeAPIKey : Разбор и проверка тела запроса на выпуск ключа API. Области действия ключа - роли
+func decodeAPIKey(body io.Reader) (key models.APIKeyRequest, err error) {
+	if err = json.NewDecoder(body).Decode(&key); err != nil {
+		return key, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err)
+	}
+
+	key.Name = strings.TrimSpace(key.Name)
+	if key.Name == "" {
+		return key, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Key name is required", nil)
+	}
+	if utf8.RuneCountInString(key.Name) > maxFieldLength {
+		return key, models.NewError(models.ErrBadRequest, models.CodeInvalidValue, fmt.Sprintf("Key name must be at most %v characters", maxFieldLength), nil)
+	}
+
+	if len(key.Scopes) == 0 {
+		return key, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "At least one scope is required", nil)
+	}
+	seen := make(map[string]bool, len(key.Scopes))
+	for _, scope := range key.Scopes {
+		if _, ok := auth.ParseRole(scope); !ok {
+			return key, models.NewError(models.ErrBadRequest, models.CodeInvalidValue, fmt.Sprintf("Unknown scope %q, expected reader, editor or admin", scope), nil)
+		}
+		if seen[scope] {
+			return key, models.NewError(models.ErrBadRequest, models.CodeInvalidValue, fmt.Sprintf("Scope %q is repeated", scope), nil)
+		}
+		seen[scope] = true
+	}
+	return key, nil
+}
+
+// createAPIKey : Обработка запроса для выпуска ключа API
+func (h *ApiHandler) createAPIKey(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("CreateAPIKey handler")
+
+	var result models.APIKeyIssued
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	key, err := decodeAPIKey(request.Body)
+	if err != nil {
+		h.loger.Errorf("Error decoding API key: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.CreateAPIKey(request.Context(), key)
+	if err != nil {
+		h.loger.Errorf("Error creating API key: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusCreated)
+}
+
+// getAPIKeysList : Обработка запроса для получения списка ключей API
+func (h *ApiHandler) getAPIKeysList(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetAPIKeysList handler")
+
+	var result models.APIKeysListResponse
+	var paginationOptions models.PaginationOptions
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	if options, ok := request.Context().Value("pagination_options").(models.PaginationOptions); ok {
+		paginationOptions = options
+	}
+	// Список ключей поддерживает только смещение
+	if paginationOptions.Offset == "" {
+		paginationOptions.Offset = defaultOffset
+	}
+
+	result, err := h.service.GetAPIKeysList(request.Context(), paginationOptions)
+	if err != nil {
+		h.loger.Errorf("Error getting API keys list: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// rotateAPIKey : Обработка запроса для замены ключа API новым
+func (h *ApiHandler) rotateAPIKey(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("RotateAPIKey handler")
+
+	var result models.APIKeyIssued
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	result, err := h.service.RotateAPIKey(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error rotating API key: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// revokeAPIKey : Обработка запроса для отзыва ключа API
+func (h *ApiHandler) revokeAPIKey(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("RevokeAPIKey handler")
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	err := h.service.RevokeAPIKey(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error revoking API key: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, models.APIKeyResponse{ID: guid}, http.StatusOK)
+}
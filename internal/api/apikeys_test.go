@@ -0,0 +1,36 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestDecodeAPIKey(t *testing.T) {
+	key, err := decodeAPIKey(strings.NewReader(`{"name": " nightly import ", "scopes": ["reader", "editor"], "expiresAt": "2030-01-01T00:00:00Z"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "nightly import", key.Name)
+	assert.Equal(t, []string{"reader", "editor"}, key.Scopes)
+	require.NotNil(t, key.ExpiresAt)
+	assert.Equal(t, 2030, key.ExpiresAt.Year())
+
+	for _, body := range []string{
+		``,
+		`{"scopes": ["reader"]}`,
+		`{"name": "job"}`,
+		`{"name": "job", "scopes": []}`,
+		`{"name": "job", "scopes": ["owner"]}`,
+		`{"name": "job", "scopes": ["reader", "reader"]}`,
+		`{"name": "` + strings.Repeat("ы", maxFieldLength+1) + `", "scopes": ["reader"]}`,
+	} {
+		_, err = decodeAPIKey(strings.NewReader(body))
+		var domainErr *models.DomainError
+		require.True(t, errors.As(err, &domainErr), body)
+		assert.Equal(t, models.ErrBadRequest, domainErr.Kind, body)
+	}
+}
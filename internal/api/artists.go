@@ -0,0 +1,218 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/a-h/respond"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"EffectiveMobile/internal/models"
+)
+
+// decodeArtist : Разбор и проверка тела запроса с названием исполнителя
+func decodeArtist(body io.Reader) (artist models.ArtistRequest, err error) {
+	if err = json.NewDecoder(body).Decode(&artist); err != nil {
+		return artist, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err)
+	}
+
+	artist.Name = strings.TrimSpace(artist.Name)
+	if artist.Name == "" {
+		return artist, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Artist name is required", nil)
+	}
+	if utf8.RuneCountInString(artist.Name) > maxFieldLength {
+		return artist, models.NewError(models.ErrBadRequest, models.CodeInvalidValue, fmt.Sprintf("Artist name must be at most %v characters", maxFieldLength), nil)
+	}
+	return artist, nil
+}
+
+// createArtist : Обработка запроса для создания исполнителя
+func (h *ApiHandler) createArtist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("CreateArtist handler")
+
+	var result models.Artist
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	artist, err := decodeArtist(request.Body)
+	if err != nil {
+		h.loger.Errorf("Error decoding artist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.CreateArtist(request.Context(), artist)
+	if err != nil {
+		h.loger.Errorf("Error creating artist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusCreated)
+}
+
+// readArtist : Обработка запроса для получения исполнителя по его ID
+func (h *ApiHandler) readArtist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("ReadArtist handler")
+
+	var result models.Artist
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	result, err := h.service.GetArtist(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error getting artist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// getArtistsList : Обработка запроса для получения списка исполнителей
+func (h *ApiHandler) getArtistsList(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetArtistsList handler")
+
+	var result models.ArtistsListResponse
+	var paginationOptions models.PaginationOptions
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	if options, ok := request.Context().Value("pagination_options").(models.PaginationOptions); ok {
+		paginationOptions = options
+	}
+	// Список исполнителей поддерживает только смещение
+	if paginationOptions.Offset == "" {
+		paginationOptions.Offset = defaultOffset
+	}
+
+	result, err := h.service.GetArtistsList(request.Context(), paginationOptions)
+	if err != nil {
+		h.loger.Errorf("Error getting artists list: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// updateArtist : Обработка запроса для переименования исполнителя
+func (h *ApiHandler) updateArtist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("UpdateArtist handler")
+
+	var result models.Artist
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	artist, err := decodeArtist(request.Body)
+	if err != nil {
+		h.loger.Errorf("Error decoding artist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.UpdateArtist(request.Context(), models.Artist{ID: guid, Name: artist.Name})
+	if err != nil {
+		h.loger.Errorf("Error updating artist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// deleteArtist : Обработка запроса для удаления исполнителя
+func (h *ApiHandler) deleteArtist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("DeleteArtist handler")
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	err := h.service.DeleteArtist(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error deleting artist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, models.ArtistResponse{ID: guid}, http.StatusOK)
+}
+
+// getArtistSongs : Обработка запроса для получения списка песен исполнителя
+func (h *ApiHandler) getArtistSongs(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetArtistSongs handler")
+
+	var result models.SongsListResponse
+	var sortOptions models.SortOptions
+	var paginationOptions models.PaginationOptions
+	var filterOptions map[string]string
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	if options, ok := request.Context().Value("sort_options").(models.SortOptions); ok {
+		sortOptions = options
+	}
+
+	if options, ok := request.Context().Value("pagination_options").(models.PaginationOptions); ok {
+		paginationOptions = options
+	}
+
+	if options, ok := request.Context().Value("filter_options").(map[string]string); ok {
+		filterOptions = options
+	}
+
+	result, err := h.service.GetArtistSongs(request.Context(), guid, sortOptions, paginationOptions, filterOptions)
+	if err != nil {
+		h.loger.Errorf("Error getting artist songs: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
@@ -0,0 +1,25 @@
+package api
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestDecodeArtist(t *testing.T) {
+	artist, err := decodeArtist(strings.NewReader(`{"name": "  Muse "}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Muse", artist.Name)
+
+	for _, body := range []string{``, `[]`, `{"name": "   "}`, `{}`, `{"name": "` + strings.Repeat("ы", maxFieldLength+1) + `"}`} {
+		_, err = decodeArtist(strings.NewReader(body))
+		var domainErr *models.DomainError
+		require.True(t, errors.As(err, &domainErr), body)
+		assert.Equal(t, models.ErrBadRequest, domainErr.Kind, body)
+	}
+}
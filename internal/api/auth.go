@@ -0,0 +1,196 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/ratelimit"
+	"EffectiveMobile/internal/reqctx"
+)
+
+const (
+	// apiKeyHeader : заголовок с ключом API сервисных клиентов
+	apiKeyHeader = "X-API-Key"
+	// bearerScheme, apiKeyScheme : названия схем безопасности в OpenAPI
+	bearerScheme = "bearerAuth"
+	apiKeyScheme = "apiKeyAuth"
+)
+
+// adminRoutes : массовые и административные маршруты, доступные только администраторам.
+// Шаблоны без завершающего "/": chi возвращает "/api/keys" для маршрута "/" внутри Route("/api/keys")
+var adminRoutes = map[string]bool{
+	"/api/songs/import":     true,
+	"/api/songs/export":     true,
+	"/api/trash":            true,
+	"/api/cache":            true,
+	"/api/keys":             true,
+	"/api/keys/{id}":        true,
+	"/api/keys/{id}/rotate": true,
+}
+
+// requiredRole : Роль, необходимая для маршрута: чтение публичное, изменения - для редакторов,
+// массовые и административные операции - для администраторов
+func requiredRole(method string, pattern string) auth.Role {
+	if adminRoutes[strings.TrimSuffix(pattern, "/")] {
+		return auth.RoleAdmin
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return auth.RoleNone
+	}
+	return auth.RoleEditor
+}
+
+// unauthorized : Ответ 401 с заголовком WWW-Authenticate (RFC 6750)
+func (h *ApiHandler) unauthorized(writer http.ResponseWriter, message string, invalidToken bool, reqID string) {
+	challenge := `Bearer realm="music-store"`
+	if invalidToken {
+		challenge += `, error="invalid_token"`
+	}
+	writer.Header().Set("WWW-Authenticate", challenge)
+	h.JSONError(writer, models.NewError(models.ErrUnauthorized, models.CodeUnauthorized, message, nil), reqID)
+}
+
+// Authenticate : Проверка Bearer токена из заголовка Authorization или ключа API из X-API-Key.
+// Запрос без них остается анонимным, с неверным токеном или ключом - отклоняется.
+// Клиент становится автором изменений в журнале ревизий
+func (h *ApiHandler) Authenticate(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		header := request.Header.Get("Authorization")
+		apiKey := request.Header.Get(apiKeyHeader)
+		reqID := middleware.GetReqID(request.Context())
+
+		var principal auth.Principal
+		switch {
+		case header != "" && apiKey != "":
+			h.unauthorized(writer, "Use either Authorization or "+apiKeyHeader+" header", false, reqID)
+			return
+		case apiKey != "":
+			// Перебор ключей ограничивается по IP до обращения к базе данных
+			if h.limiter != nil && !h.takeRateLimit(writer, request, ratelimit.GroupAuth, clientIP(request)) {
+				return
+			}
+			key, err := h.service.AuthenticateAPIKey(request.Context(), apiKey)
+			if errors.Is(err, models.ErrUnauthorized) {
+				reqctx.Logger(request.Context(), h.loger).Warnln("Rejected API key")
+				h.unauthorized(writer, "API key is not valid", true, reqID)
+				return
+			}
+			if err != nil {
+				h.JSONError(writer, err, reqID)
+				return
+			}
+			principal = auth.Principal{Subject: "key:" + key.ID, Role: auth.HighestRole(key.Scopes), KeyID: key.ID}
+		case header != "" && h.auth != nil:
+			scheme, token, ok := strings.Cut(header, " ")
+			if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+				h.unauthorized(writer, "Authorization header must be a Bearer token", false, reqID)
+				return
+			}
+			var err error
+			principal, err = h.auth.Verify(strings.TrimSpace(token))
+			if err != nil {
+				reqctx.Logger(request.Context(), h.loger).Warnf("Rejected token: %v", err)
+				h.unauthorized(writer, "Token is not valid", true, reqID)
+				return
+			}
+		default:
+			handler.ServeHTTP(writer, request)
+			return
+		}
+
+		ctx := auth.WithPrincipal(request.Context(), principal)
+		ctx = reqctx.WithActor(ctx, principal.Subject)
+		if principal.KeyID != "" {
+			// ID ключа попадает во все логи запроса
+			ctx = reqctx.WithLogger(ctx, reqctx.Logger(ctx, h.loger).With("apiKeyId", principal.KeyID))
+			if entry, ok := requestLogFrom(ctx); ok {
+				entry.apiKeyID = principal.KeyID
+			}
+		}
+		handler.ServeHTTP(writer, request.WithContext(ctx))
+	})
+}
+
+// Authorize : Проверка роли клиента для маршрута routes, который обработает запрос.
+// Неизвестные маршруты пропускаются, чтобы роутер ответил 404 или 405. Проверка не зависит
+// от настройки JWT: без нее закрытые маршруты доступны только по ключам API
+func (h *ApiHandler) Authorize(routes chi.Routes) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			rctx := chi.NewRouteContext()
+			if !routes.Match(rctx, request.Method, request.URL.Path) {
+				handler.ServeHTTP(writer, request)
+				return
+			}
+
+			required := requiredRole(request.Method, rctx.RoutePattern())
+			principal, authenticated := auth.FromContext(request.Context())
+			if principal.Role.Allows(required) {
+				handler.ServeHTTP(writer, request)
+				return
+			}
+
+			reqID := middleware.GetReqID(request.Context())
+			message := fmt.Sprintf("Role %v is required", required)
+			if !authenticated {
+				h.unauthorized(writer, message, false, reqID)
+				return
+			}
+			h.JSONError(writer, models.NewError(models.ErrForbidden, models.CodeForbidden, message, nil), reqID)
+		})
+	}
+}
+
+// documentSecurity : Схемы безопасности JWT и ключа API в OpenAPI и требования к роли для закрытых маршрутов
+func documentSecurity(spec *openapi3.T) {
+	if spec.Components.SecuritySchemes == nil {
+		spec.Components.SecuritySchemes = openapi3.SecuritySchemes{}
+	}
+	spec.Components.SecuritySchemes[bearerScheme] = &openapi3.SecuritySchemeRef{
+		Value: openapi3.NewJWTSecurityScheme().
+			WithDescription("HS256 or RS256 JWT with the roles claim: reader, editor or admin"),
+	}
+	spec.Components.SecuritySchemes[apiKeyScheme] = &openapi3.SecuritySchemeRef{
+		Value: openapi3.NewSecurityScheme().WithType("apiKey").WithIn("header").WithName(apiKeyHeader).
+			WithDescription("API key issued by an admin; its scopes are roles: reader, editor or admin"),
+	}
+
+	for path, item := range spec.Paths.Map() {
+		for method, operation := range item.Operations() {
+			required := requiredRole(method, path)
+			if required == auth.RoleNone {
+				continue
+			}
+
+			operation.Security = openapi3.NewSecurityRequirements().
+				With(openapi3.NewSecurityRequirement().Authenticate(bearerScheme)).
+				With(openapi3.NewSecurityRequirement().Authenticate(apiKeyScheme))
+			if operation.Description == "" {
+				operation.Description = fmt.Sprintf("Requires the %v role", required)
+			} else {
+				operation.Description += fmt.Sprintf("; requires the %v role", required)
+			}
+
+			// Ошибки доступа описываются той же моделью, что и остальные ошибки маршрута
+			if errorResponse := operation.Responses.Status(http.StatusInternalServerError); errorResponse != nil && errorResponse.Value != nil {
+				for status, description := range map[int]string{
+					http.StatusUnauthorized: "Missing or invalid token or API key",
+					http.StatusForbidden:    "Role is not sufficient",
+				} {
+					response := *errorResponse.Value
+					response.Description = &description
+					operation.Responses.Set(fmt.Sprint(status), &openapi3.ResponseRef{Value: &response})
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/ratelimit"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/service"
+	"EffectiveMobile/internal/storage"
+)
+
+var authSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func authToken(t *testing.T, roles ...string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "alice",
+		"roles": roles,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString(authSecret)
+	require.NoError(t, err)
+	return token
+}
+
+// authRouter : Роутер с маршрутами каждого уровня доступа, обработчики возвращают автора запроса
+func authRouter(t *testing.T, verifier *auth.Verifier) *chi.Mux {
+	h := NewHandler(nil, verifier, nil, nil, zap.NewNop().Sugar())
+	ok := func(writer http.ResponseWriter, request *http.Request) {
+		_, _ = writer.Write([]byte(reqctx.Actor(request.Context())))
+	}
+
+	router := chi.NewRouter()
+	router.Use(h.Authenticate)
+	router.Use(h.Authorize(router))
+	router.Get("/api/song/{id}", ok)
+	router.Delete("/api/song/{id}", ok)
+	router.Route("/api/songs", func(r chi.Router) {
+		r.Get("/", ok)
+		r.Get("/export", ok)
+	})
+	router.Route("/api/keys", func(r chi.Router) {
+		r.Get("/", ok)
+		r.Post("/", ok)
+	})
+	return router
+}
+
+func TestRequiredRole(t *testing.T) {
+	assert.Equal(t, auth.RoleNone, requiredRole(http.MethodGet, "/api/song/{id}"))
+	assert.Equal(t, auth.RoleEditor, requiredRole(http.MethodDelete, "/api/song/{id}"))
+	assert.Equal(t, auth.RoleEditor, requiredRole(http.MethodPost, "/api/playlists/{id}/entries"))
+	assert.Equal(t, auth.RoleAdmin, requiredRole(http.MethodPost, "/api/songs/import"))
+	assert.Equal(t, auth.RoleAdmin, requiredRole(http.MethodGet, "/api/songs/export"))
+	assert.Equal(t, auth.RoleAdmin, requiredRole(http.MethodGet, "/api/trash"))
+	assert.Equal(t, auth.RoleAdmin, requiredRole(http.MethodGet, "/api/keys/"))
+	assert.Equal(t, auth.RoleAdmin, requiredRole(http.MethodPost, "/api/keys"))
+	assert.Equal(t, auth.RoleAdmin, requiredRole(http.MethodPost, "/api/keys/{id}/rotate"))
+}
+
+func TestAuthorize(t *testing.T) {
+	verifier, err := auth.NewVerifier(auth.Config{Secret: authSecret})
+	require.NoError(t, err)
+	router := authRouter(t, verifier)
+
+	cases := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{"public read", http.MethodGet, "/api/song/1", "", http.StatusOK},
+		{"anonymous write", http.MethodDelete, "/api/song/1", "", http.StatusUnauthorized},
+		{"reader write", http.MethodDelete, "/api/song/1", authToken(t, "reader"), http.StatusForbidden},
+		{"editor write", http.MethodDelete, "/api/song/1", authToken(t, "editor"), http.StatusOK},
+		{"editor bulk", http.MethodGet, "/api/songs/export", authToken(t, "editor"), http.StatusForbidden},
+		{"admin bulk", http.MethodGet, "/api/songs/export", authToken(t, "admin"), http.StatusOK},
+		{"anonymous keys list", http.MethodGet, "/api/keys/", "", http.StatusUnauthorized},
+		{"editor key issue", http.MethodPost, "/api/keys/", authToken(t, "editor"), http.StatusForbidden},
+		{"admin key issue", http.MethodPost, "/api/keys/", authToken(t, "admin"), http.StatusOK},
+		{"invalid token on public read", http.MethodGet, "/api/songs/", "not.a.token", http.StatusUnauthorized},
+		{"unknown route", http.MethodDelete, "/api/unknown", "", http.StatusNotFound},
+	}
+
+	for _, c := range cases {
+		request := httptest.NewRequest(c.method, c.path, nil)
+		if c.token != "" {
+			request.Header.Set("Authorization", "Bearer "+c.token)
+		}
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+
+		assert.Equal(t, c.status, recorder.Code, c.name)
+		if c.status == http.StatusUnauthorized {
+			assert.Contains(t, recorder.Header().Get("WWW-Authenticate"), "Bearer", c.name)
+		}
+		if c.status == http.StatusOK && c.token != "" {
+			assert.Equal(t, "alice", recorder.Body.String(), c.name)
+		}
+	}
+
+	// Basic схема не поддерживается
+	request := httptest.NewRequest(http.MethodGet, "/api/song/1", nil)
+	request.Header.Set("Authorization", "Basic YWxpY2U6c2VjcmV0")
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	// Токен и ключ API одновременно не принимаются
+	request = httptest.NewRequest(http.MethodGet, "/api/song/1", nil)
+	request.Header.Set("Authorization", "Bearer "+authToken(t, "admin"))
+	request.Header.Set(apiKeyHeader, "msk_key")
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+func TestAuthorizeWithoutVerifier(t *testing.T) {
+	router := authRouter(t, nil)
+
+	// Чтение остается публичным
+	request := httptest.NewRequest(http.MethodGet, "/api/song/1", nil)
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	// Без проверки токенов закрытые маршруты не открываются, анонимный клиент не выпустит ключ API
+	for _, request := range []*http.Request{
+		httptest.NewRequest(http.MethodDelete, "/api/song/1", nil),
+		httptest.NewRequest(http.MethodGet, "/api/songs/export", nil),
+		httptest.NewRequest(http.MethodPost, "/api/keys/", nil),
+	} {
+		request.Header.Set("Authorization", "Bearer not.a.token")
+		recorder = httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, request.URL.Path)
+	}
+
+	request = httptest.NewRequest(http.MethodPost, "/api/keys/", nil)
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+}
+
+// keyStorage : хранилище без ключей API, которое считает поиски ключей
+type keyStorage struct {
+	storage.SongStorage
+	lookups int
+}
+
+func (s *keyStorage) UseAPIKey(ctx context.Context, hash string) (models.APIKey, error) {
+	s.lookups++
+	return models.APIKey{}, models.ErrNotFound
+}
+
+func TestAuthenticateAPIKeyRateLimit(t *testing.T) {
+	store := &keyStorage{}
+	limiter := ratelimit.NewLimiter(ratelimit.Config{
+		Auth: ratelimit.Limit{Requests: 1, Period: time.Minute},
+	}, ratelimit.NewMemoryStore())
+	h := NewHandler(service.NewService(store, nil, nil, zap.NewNop().Sugar()), nil, limiter, nil, zap.NewNop().Sugar())
+
+	router := chi.NewRouter()
+	router.Use(h.Authenticate)
+	router.Get("/api/song/{id}", func(writer http.ResponseWriter, request *http.Request) {})
+
+	send := func(remoteAddr string) int {
+		request := httptest.NewRequest(http.MethodGet, "/api/song/1", nil)
+		request.RemoteAddr = remoteAddr
+		request.Header.Set(apiKeyHeader, "guessed-key")
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, send("10.0.0.1:5000"))
+	// Исчерпанный лимит IP отклоняет запрос до поиска ключа
+	assert.Equal(t, http.StatusTooManyRequests, send("10.0.0.1:5001"))
+	assert.Equal(t, 1, store.lookups)
+
+	assert.Equal(t, http.StatusUnauthorized, send("10.0.0.2:5000"))
+	assert.Equal(t, 2, store.lookups)
+}
+
+func TestDocumentSecurity(t *testing.T) {
+	NewHandler(nil, nil, nil, nil, zap.NewNop().Sugar()).InitRoutes()
+
+	require.Contains(t, spec.Components.SecuritySchemes, bearerScheme)
+	assert.Equal(t, "bearer", spec.Components.SecuritySchemes[bearerScheme].Value.Scheme)
+	require.Contains(t, spec.Components.SecuritySchemes, apiKeyScheme)
+	assert.Equal(t, apiKeyHeader, spec.Components.SecuritySchemes[apiKeyScheme].Value.Name)
+
+	create := spec.Paths.Value("/api/song").Post
+	require.NotNil(t, create.Security)
+	assert.Len(t, *create.Security, 2)
+	assert.Contains(t, create.Description, "editor")
+	assert.NotNil(t, create.Responses.Status(http.StatusUnauthorized))
+	assert.NotNil(t, create.Responses.Status(http.StatusForbidden))
+
+	assert.Contains(t, spec.Paths.Value("/api/songs/export").Get.Description, "admin")
+	assert.Nil(t, spec.Paths.Value("/api/song/{id}").Get.Security)
+}
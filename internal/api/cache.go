@@ -0,0 +1,20 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/a-h/respond"
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// getCacheStats : Обработка запроса для получения счетчиков кэша чтения песен
+func (h *ApiHandler) getCacheStats(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetCacheStats handler")
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	respond.WithJSON(writer, h.service.GetCacheStats(request.Context()), http.StatusOK)
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"fmt"
+	"strings"
+)
+
+// etag : Значение заголовка ETag для версии песни
+func etag(version int) string {
+	return fmt.Sprintf(`"v%d"`, version)
+}
+
+// ifMatchVersions : Версии песни из заголовка If-Match.
+// nil - заголовка нет или передан "*", пустой список - ни один тег не совпадет с песней.
+// If-Match использует строгое сравнение, поэтому слабые теги W/ не учитываются
+func ifMatchVersions(header string) []int {
+	if strings.TrimSpace(header) == "" {
+		return nil
+	}
+
+	versions := []int{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return nil
+		}
+		if version, ok := parseETag(tag); ok {
+			versions = append(versions, version)
+		}
+	}
+	return versions
+}
+
+// noneMatch : Проверка заголовка If-None-Match для песни версии version.
+// Возвращает false, если клиент уже имеет актуальную версию (слабое сравнение)
+func noneMatch(header string, version int) bool {
+	if strings.TrimSpace(header) == "" {
+		return true
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" {
+			return false
+		}
+		if tagVersion, ok := parseETag(strings.TrimPrefix(tag, "W/")); ok && tagVersion == version {
+			return false
+		}
+	}
+	return true
+}
+
+// parseETag : Разбор строгого тега вида "v<версия>"
+func parseETag(tag string) (int, bool) {
+	var version int
+	if _, err := fmt.Sscanf(tag, `"v%d"`, &version); err != nil || etag(version) != tag {
+		return 0, false
+	}
+	return version, true
+}
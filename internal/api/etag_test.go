@@ -0,0 +1,25 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIfMatchVersions(t *testing.T) {
+	assert.Nil(t, ifMatchVersions(""))
+	assert.Nil(t, ifMatchVersions("*"))
+	assert.Equal(t, []int{3}, ifMatchVersions(`"v3"`))
+	assert.Equal(t, []int{1, 2}, ifMatchVersions(`"v1", "v2"`))
+	assert.Equal(t, []int{}, ifMatchVersions(`W/"v3"`))
+	assert.Equal(t, []int{}, ifMatchVersions(`"v3x", 3, "abc"`))
+}
+
+func TestNoneMatch(t *testing.T) {
+	assert.True(t, noneMatch("", 3))
+	assert.True(t, noneMatch(`"v2"`, 3))
+	assert.False(t, noneMatch(`"v3"`, 3))
+	assert.False(t, noneMatch(`W/"v3"`, 3))
+	assert.False(t, noneMatch(`"v1", "v3"`, 3))
+	assert.False(t, noneMatch("*", 3))
+}
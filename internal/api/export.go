@@ -0,0 +1,109 @@
+package api
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+
+	"EffectiveMobile/internal/models"
+)
+
+// exportFlushRows : через сколько строк выгрузки данные отправляются клиенту
+const exportFlushRows = 100
+
+// exportColumns : колонки CSV выгрузки, совпадают с заголовком файла импорта
+var exportColumns = []string{"id", "song", "group", "releaseDate", "text", "link"}
+
+// exportWriter : Потоковая запись песен в ответ в формате NDJSON или CSV.
+// Заголовки ответа отправляются вместе с первой строкой, чтобы до нее можно было вернуть JSON ошибку
+type exportWriter struct {
+	writer     http.ResponseWriter
+	controller *http.ResponseController
+	format     string
+	csv        *csv.Writer
+	json       *json.Encoder
+	started    bool
+	rows       int
+}
+
+func newExportWriter(writer http.ResponseWriter, format string) *exportWriter {
+	return &exportWriter{
+		writer:     writer,
+		controller: http.NewResponseController(writer),
+		format:     format,
+		csv:        csv.NewWriter(writer),
+		json:       json.NewEncoder(writer),
+	}
+}
+
+// exportFormat : Проверка формата выгрузки, по умолчанию NDJSON
+func exportFormat(format string) (string, error) {
+	switch format {
+	case "":
+		return "ndjson", nil
+	case "ndjson", "csv":
+		return format, nil
+	}
+	return "", models.NewError(models.ErrBadRequest, models.CodeUnsupportedFormat, "Export format must be ndjson or csv", nil)
+}
+
+func (e *exportWriter) start() error {
+	e.started = true
+
+	if e.format == "csv" {
+		e.writer.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		e.writer.Header().Set("Content-Disposition", `attachment; filename="songs.csv"`)
+		e.writer.WriteHeader(http.StatusOK)
+		return e.csv.Write(exportColumns)
+	}
+
+	e.writer.Header().Set("Content-Type", "application/x-ndjson")
+	e.writer.Header().Set("Content-Disposition", `attachment; filename="songs.ndjson"`)
+	e.writer.WriteHeader(http.StatusOK)
+	return nil
+}
+
+// Write : Запись одной песни
+func (e *exportWriter) Write(song models.Song) error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if e.format == "csv" {
+		err = e.csv.Write([]string{song.ID, song.Name, song.Artist, song.Release, song.Text, song.Link})
+	} else {
+		err = e.json.Encode(song)
+	}
+	if err != nil {
+		return err
+	}
+
+	e.rows++
+	if e.rows%exportFlushRows == 0 {
+		return e.flush()
+	}
+	return nil
+}
+
+// Close : Отправка оставшихся строк, для пустой выгрузки - только заголовков
+func (e *exportWriter) Close() error {
+	if !e.started {
+		if err := e.start(); err != nil {
+			return err
+		}
+	}
+	return e.flush()
+}
+
+func (e *exportWriter) flush() error {
+	if e.format == "csv" {
+		e.csv.Flush()
+		if err := e.csv.Error(); err != nil {
+			return err
+		}
+	}
+	return e.controller.Flush()
+}
@@ -0,0 +1,79 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/negroni"
+
+	"EffectiveMobile/internal/models"
+)
+
+var exportSong = models.Song{
+	ID:      "7b0c2a8e-4f0d-4d4e-9b8a-3c1f2d5e6a7b",
+	Name:    "Supermassive Black Hole",
+	Artist:  "Muse",
+	Release: "16.07.2006",
+	Text:    "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?",
+}
+
+func TestExportWriterCSV(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	export := newExportWriter(recorder, "csv")
+
+	require.NoError(t, export.Write(exportSong))
+	require.NoError(t, export.Close())
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "text/csv; charset=utf-8", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, "id,song,group,releaseDate,text,link\n"+
+		"7b0c2a8e-4f0d-4d4e-9b8a-3c1f2d5e6a7b,Supermassive Black Hole,Muse,16.07.2006,\"Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\",\n",
+		recorder.Body.String())
+	assert.True(t, recorder.Flushed)
+}
+
+func TestExportWriterNDJSON(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	export := newExportWriter(recorder, "ndjson")
+
+	require.NoError(t, export.Write(exportSong))
+	require.NoError(t, export.Write(models.Song{ID: "1", Name: "Hysteria", Artist: "Muse"}))
+	require.NoError(t, export.Close())
+
+	assert.Equal(t, "application/x-ndjson", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `{"id":"7b0c2a8e-4f0d-4d4e-9b8a-3c1f2d5e6a7b","song":"Supermassive Black Hole","group":"Muse","releaseDate":"16.07.2006","text":"Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?"}`+"\n"+
+		`{"id":"1","song":"Hysteria","group":"Muse"}`+"\n",
+		recorder.Body.String())
+}
+
+func TestExportWriterEmpty(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	require.NoError(t, newExportWriter(recorder, "csv").Close())
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "id,song,group,releaseDate,text,link\n", recorder.Body.String())
+}
+
+// deadlineRecorder : ResponseWriter, поддерживающий снятие дедлайна записи
+type deadlineRecorder struct {
+	*httptest.ResponseRecorder
+	deadline *time.Time
+}
+
+func (r deadlineRecorder) SetWriteDeadline(deadline time.Time) error {
+	*r.deadline = deadline
+	return nil
+}
+
+func TestLogResponseWriterUnwrap(t *testing.T) {
+	deadline := time.Now()
+	writer := deadlineRecorder{ResponseRecorder: httptest.NewRecorder(), deadline: &deadline}
+	lrw := logResponseWriter{ResponseWriter: negroni.NewResponseWriter(writer), writer: writer}
+
+	require.NoError(t, http.NewResponseController(lrw).SetWriteDeadline(time.Time{}))
+	assert.True(t, deadline.IsZero())
+}
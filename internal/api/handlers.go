@@ -3,8 +3,9 @@ package api
 import (
 	"bytes"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/a-h/respond"
@@ -12,14 +13,18 @@ import (
 	"github.com/go-chi/chi/v5/middleware"
 	"go.uber.org/zap"
 
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/health"
 	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/ratelimit"
 	"EffectiveMobile/internal/service"
 )
 
-var err error
-
 type ApiHandler struct {
 	service *service.Service
+	auth    *auth.Verifier
+	limiter *ratelimit.Limiter
+	health  *health.Checker
 	loger   *zap.SugaredLogger
 	SongAPI
 }
@@ -28,15 +33,60 @@ type SongAPI interface {
 	createSong(writer http.ResponseWriter, request *http.Request)
 	readSong(writer http.ResponseWriter, request *http.Request)
 	updateSong(writer http.ResponseWriter, request *http.Request)
+	patchSong(writer http.ResponseWriter, request *http.Request)
 	deleteSong(writer http.ResponseWriter, request *http.Request)
 	getSongInfo(writer http.ResponseWriter, request *http.Request)
 	getSongsList(writer http.ResponseWriter, request *http.Request)
 	getSongCouplet(writer http.ResponseWriter, request *http.Request)
+	searchSongs(writer http.ResponseWriter, request *http.Request)
+	getSongRevisions(writer http.ResponseWriter, request *http.Request)
+	diffSongRevisions(writer http.ResponseWriter, request *http.Request)
+	revertSong(writer http.ResponseWriter, request *http.Request)
+	getTrash(writer http.ResponseWriter, request *http.Request)
+	restoreSong(writer http.ResponseWriter, request *http.Request)
+	importSongs(writer http.ResponseWriter, request *http.Request)
+	exportSongs(writer http.ResponseWriter, request *http.Request)
+	getSongLyrics(writer http.ResponseWriter, request *http.Request)
+	createArtist(writer http.ResponseWriter, request *http.Request)
+	readArtist(writer http.ResponseWriter, request *http.Request)
+	getArtistsList(writer http.ResponseWriter, request *http.Request)
+	updateArtist(writer http.ResponseWriter, request *http.Request)
+	deleteArtist(writer http.ResponseWriter, request *http.Request)
+	getArtistSongs(writer http.ResponseWriter, request *http.Request)
+	createAlbum(writer http.ResponseWriter, request *http.Request)
+	readAlbum(writer http.ResponseWriter, request *http.Request)
+	getAlbumsList(writer http.ResponseWriter, request *http.Request)
+	updateAlbum(writer http.ResponseWriter, request *http.Request)
+	deleteAlbum(writer http.ResponseWriter, request *http.Request)
+	setAlbumTracks(writer http.ResponseWriter, request *http.Request)
+	addAlbumTrack(writer http.ResponseWriter, request *http.Request)
+	createPlaylist(writer http.ResponseWriter, request *http.Request)
+	readPlaylist(writer http.ResponseWriter, request *http.Request)
+	getPlaylistsList(writer http.ResponseWriter, request *http.Request)
+	updatePlaylist(writer http.ResponseWriter, request *http.Request)
+	deletePlaylist(writer http.ResponseWriter, request *http.Request)
+	addPlaylistEntry(writer http.ResponseWriter, request *http.Request)
+	removePlaylistEntry(writer http.ResponseWriter, request *http.Request)
+	movePlaylistEntry(writer http.ResponseWriter, request *http.Request)
+	exportPlaylist(writer http.ResponseWriter, request *http.Request)
+	createAPIKey(writer http.ResponseWriter, request *http.Request)
+	getAPIKeysList(writer http.ResponseWriter, request *http.Request)
+	rotateAPIKey(writer http.ResponseWriter, request *http.Request)
+	revokeAPIKey(writer http.ResponseWriter, request *http.Request)
+	getCacheStats(writer http.ResponseWriter, request *http.Request)
+	healthz(writer http.ResponseWriter, request *http.Request)
+	readyz(writer http.ResponseWriter, request *http.Request)
 }
 
-func NewHandler(service *service.Service, loger *zap.SugaredLogger) *ApiHandler {
+// NewHandler : verifier равный nil отключает прием токенов: чтение остается публичным, остальные маршруты доступны только по ключам API.
+// limiter равный nil отключает ограничение частоты запросов.
+// checker равный nil оставляет приложение готовым без проверки зависимостей
+func NewHandler(service *service.Service, verifier *auth.Verifier, limiter *ratelimit.Limiter, checker *health.Checker, loger *zap.SugaredLogger) *ApiHandler {
 	return &ApiHandler{
 		service: service,
+		auth:    verifier,
+		limiter: limiter,
+		health:  checker,
 		loger:   loger,
 	}
 }
@@ -54,29 +104,29 @@ func (h *ApiHandler) createSong(writer http.ResponseWriter, request *http.Reques
 	method := request.Method
 	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
 
-	_, err = buf.ReadFrom(request.Body)
+	_, err := buf.ReadFrom(request.Body)
 	if err != nil {
 		h.loger.Errorf("Error reading request body: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error reading request body: %v", err.Error()), http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Error reading request body", err), reqID)
 		return
 	}
 
 	if err = json.Unmarshal(buf.Bytes(), &song); err != nil {
 		h.loger.Errorf("Error unmarshalling request body: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error unmarshalling request body: %v", err.Error()), http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err), reqID)
 		return
 	}
 
 	if song.Name == "" || song.Artist == "" {
 		h.loger.Errorf("Song name, artist not filled")
-		h.JSONError(writer, "Song name, artist are required", http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Song name, artist are required", nil), reqID)
 		return
 	}
 
-	result.ID, err = h.service.CreateSong(song, reqID)
+	result.ID, err = h.service.CreateSong(request.Context(), song)
 	if err != nil {
 		h.loger.Errorf("Error creating song: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error creating song: %v", err.Error()), http.StatusInternalServerError, reqID)
+		h.JSONError(writer, err, reqID)
 		return
 	}
 
@@ -96,16 +146,22 @@ func (h *ApiHandler) readSong(writer http.ResponseWriter, request *http.Request)
 
 	guid := chi.URLParam(request, "id")
 	if guid == "" {
-		h.JSONError(writer, "ID is required", http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
 		return
 	}
 
-	result, err = h.service.ReadSong(guid, reqID)
+	result, err := h.service.ReadSong(request.Context(), guid)
 
 	h.loger.Debugln(result)
 
 	if err != nil {
-		h.JSONError(writer, fmt.Sprintf("Error reading song: %v", err.Error()), http.StatusInternalServerError, reqID)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	writer.Header().Set("ETag", etag(result.Version))
+	if !noneMatch(request.Header.Get("If-None-Match"), result.Version) {
+		writer.WriteHeader(http.StatusNotModified)
 		return
 	}
 
@@ -125,43 +181,92 @@ func (h *ApiHandler) updateSong(writer http.ResponseWriter, request *http.Reques
 	method := request.Method
 	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
 
-	_, err = buf.ReadFrom(request.Body)
+	_, err := buf.ReadFrom(request.Body)
 	if err != nil {
 		h.loger.Errorf("Error reading request body: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error reading request body: %v", err.Error()), http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Error reading request body", err), reqID)
 		return
 	}
 
 	if err = json.Unmarshal(buf.Bytes(), &song); err != nil {
 		h.loger.Errorf("Error unmarshalling request body: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error unmarshalling request body: %v", err.Error()), http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err), reqID)
 		return
 	}
 
 	if song.ID == "" || song.Name == "" || song.Artist == "" {
 		h.loger.Errorf("Song name, artist not filled")
-		h.JSONError(writer, "Song id, name, artist are required", http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Song id, name, artist are required", nil), reqID)
 		return
 	}
 
-	if song.Release != "" {
-		_, err = time.Parse("02.01.2006", song.Release)
-		if err != nil {
-			h.loger.Errorf("Error parsing release date: %v", err)
-			h.JSONError(writer, fmt.Sprintf("Error parsing release date: %v", err.Error()), http.StatusBadRequest, reqID)
-			return
-		}
+	if err = validateRelease(song.Release); err != nil {
+		h.loger.Errorf("Error parsing release date: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
 	}
 
-	result, err = h.service.UpdateSong(song, reqID)
+	if err = validateLink(song.Link); err != nil {
+		h.loger.Errorf("Error parsing link: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.UpdateSong(request.Context(), song, ifMatchVersions(request.Header.Get("If-Match")))
 	if err != nil {
 		h.loger.Errorf("Error updating song: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error updating song: %v", err.Error()), http.StatusInternalServerError, reqID)
+		h.JSONError(writer, err, reqID)
 		return
 	}
 
 	h.loger.Debugln(result)
 
+	writer.Header().Set("ETag", etag(result.Version))
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// patchSong : Обработка запроса для частичного обновления песни (JSON Merge Patch)
+func (h *ApiHandler) patchSong(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("PatchSong handler")
+
+	var patch models.SongPatch
+	var result models.Song
+	var buf bytes.Buffer
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	_, err := buf.ReadFrom(request.Body)
+	if err != nil {
+		h.loger.Errorf("Error reading request body: %v", err)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Error reading request body", err), reqID)
+		return
+	}
+
+	patch, err = decodeSongPatch(buf.Bytes(), guid)
+	if err != nil {
+		h.loger.Errorf("Error decoding merge patch: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.PatchSong(request.Context(), guid, patch, ifMatchVersions(request.Header.Get("If-Match")))
+	if err != nil {
+		h.loger.Errorf("Error patching song: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	writer.Header().Set("ETag", etag(result.Version))
+
 	respond.WithJSON(writer, result, http.StatusOK)
 }
 
@@ -178,13 +283,13 @@ func (h *ApiHandler) deleteSong(writer http.ResponseWriter, request *http.Reques
 
 	guid := chi.URLParam(request, "id")
 	if guid == "" {
-		h.JSONError(writer, "ID is required", http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
 		return
 	}
 
-	result, err = h.service.DeleteSong(guid, reqID)
+	result, err := h.service.DeleteSong(request.Context(), guid, ifMatchVersions(request.Header.Get("If-Match")))
 	if err != nil {
-		h.JSONError(writer, fmt.Sprintf("Error deleting song: %v", err.Error()), http.StatusInternalServerError, reqID)
+		h.JSONError(writer, err, reqID)
 		return
 	}
 
@@ -204,29 +309,29 @@ func (h *ApiHandler) getSongInfo(writer http.ResponseWriter, request *http.Reque
 	method := request.Method
 	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
 
-	_, err = buf.ReadFrom(request.Body)
+	_, err := buf.ReadFrom(request.Body)
 	if err != nil {
 		h.loger.Errorf("Error reading request body: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error reading request body: %v", err.Error()), http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Error reading request body", err), reqID)
 		return
 	}
 
 	if err = json.Unmarshal(buf.Bytes(), &song); err != nil {
 		h.loger.Errorf("Error unmarshalling request body: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error unmarshalling request body: %v", err.Error()), http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err), reqID)
 		return
 	}
 
 	if song.Name == "" || song.Artist == "" {
 		h.loger.Errorf("Song name, artist not filled")
-		h.JSONError(writer, "Song name, artist are required", http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Song name, artist are required", nil), reqID)
 		return
 	}
 
-	result, err = h.service.GetSongInfo(song, reqID)
+	result, err = h.service.GetSongInfo(request.Context(), song)
 	if err != nil {
 		h.loger.Errorf("Error getting song info: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error getting song info: %v", err.Error()), http.StatusInternalServerError, reqID)
+		h.JSONError(writer, err, reqID)
 		return
 	}
 
@@ -259,10 +364,57 @@ func (h *ApiHandler) getSongsList(writer http.ResponseWriter, request *http.Requ
 		filterOptions = options
 	}
 
-	result, err = h.service.GetSongsList(reqID, sortOptions, paginationOptions, filterOptions)
+	result, err := h.service.GetSongsList(request.Context(), sortOptions, paginationOptions, filterOptions)
 	if err != nil {
 		h.loger.Errorf("Error getting songs list: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error getting songs list: %v", err.Error()), http.StatusInternalServerError, reqID)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// searchSongs : Обработка запроса для полнотекстового поиска песен
+func (h *ApiHandler) searchSongs(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("SearchSongs handler")
+
+	var result models.SongsSearchResponse
+	var paginationOptions models.PaginationOptions
+	var filterOptions map[string]string
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	text := request.URL.Query().Get("q")
+	if text == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Search query q is required", nil), reqID)
+		return
+	}
+
+	lang := request.URL.Query().Get("lang")
+	if lang != "" && lang != "ru" && lang != "en" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Lang must be ru or en", nil), reqID)
+		return
+	}
+
+	if options, ok := request.Context().Value("pagination_options").(models.PaginationOptions); ok {
+		paginationOptions = options
+	}
+	// Поиск поддерживает только смещение
+	if paginationOptions.Offset == "" {
+		paginationOptions.Offset = defaultOffset
+	}
+
+	if options, ok := request.Context().Value("filter_options").(map[string]string); ok {
+		filterOptions = options
+	}
+
+	result, err := h.service.SearchSongs(request.Context(), text, lang, paginationOptions, filterOptions)
+	if err != nil {
+		h.loger.Errorf("Error searching songs: %v", err)
+		h.JSONError(writer, err, reqID)
 		return
 	}
 
@@ -282,7 +434,7 @@ func (h *ApiHandler) getSongCouplet(writer http.ResponseWriter, request *http.Re
 
 	guid := chi.URLParam(request, "id")
 	if guid == "" {
-		h.JSONError(writer, "ID is required", http.StatusBadRequest, reqID)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
 		return
 	}
 
@@ -291,28 +443,306 @@ func (h *ApiHandler) getSongCouplet(writer http.ResponseWriter, request *http.Re
 		coupletStr = defaultСouplet
 	}
 
-	result, err = h.service.GetSongCouplet(guid, coupletStr, reqID)
+	result, err := h.service.GetSongCouplet(request.Context(), guid, coupletStr)
 	if err != nil {
 		h.loger.Errorf("Error getting song verses: %v", err)
-		h.JSONError(writer, fmt.Sprintf("Error getting song verses: %v", err.Error()), http.StatusInternalServerError, reqID)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// getSongRevisions : Обработка запроса для получения журнала ревизий песни
+func (h *ApiHandler) getSongRevisions(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetSongRevisions handler")
+
+	var result models.SongRevisionsResponse
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	result, err := h.service.GetSongRevisions(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error getting song revisions: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// diffSongRevisions : Обработка запроса для сравнения двух ревизий песни
+func (h *ApiHandler) diffSongRevisions(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("DiffSongRevisions handler")
+
+	var result models.SongRevisionDiff
+	var from, to int
+	var err error
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	if from, err = parseRevision("from", request.URL.Query().Get("from")); err != nil {
+		h.JSONError(writer, err, reqID)
+		return
+	}
+	if to, err = parseRevision("to", request.URL.Query().Get("to")); err != nil {
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.DiffSongRevisions(request.Context(), guid, from, to)
+	if err != nil {
+		h.loger.Errorf("Error diffing song revisions: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// revertSong : Обработка запроса для возврата песни к ревизии
+func (h *ApiHandler) revertSong(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("RevertSong handler")
+
+	var result models.Song
+	var revision int
+	var err error
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	if revision, err = parseRevision("rev", chi.URLParam(request, "rev")); err != nil {
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.RevertSong(request.Context(), guid, revision, ifMatchVersions(request.Header.Get("If-Match")))
+	if err != nil {
+		h.loger.Errorf("Error reverting song: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	writer.Header().Set("ETag", etag(result.Version))
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// getTrash : Обработка запроса для получения списка удаленных песен
+func (h *ApiHandler) getTrash(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetTrash handler")
+
+	var result models.TrashResponse
+	var paginationOptions models.PaginationOptions
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	if options, ok := request.Context().Value("pagination_options").(models.PaginationOptions); ok {
+		paginationOptions = options
+	}
+	// Корзина поддерживает только смещение
+	if paginationOptions.Offset == "" {
+		paginationOptions.Offset = defaultOffset
+	}
+
+	result, err := h.service.GetTrash(request.Context(), paginationOptions)
+	if err != nil {
+		h.loger.Errorf("Error getting trash: %v", err)
+		h.JSONError(writer, err, reqID)
 		return
 	}
 
 	respond.WithJSON(writer, result, http.StatusOK)
 }
 
-// JSONError : Обработка ошибок в JSON формате
-func (h *ApiHandler) JSONError(w http.ResponseWriter, error string, code int, reqID string) {
+// restoreSong : Обработка запроса для восстановления песни из корзины
+func (h *ApiHandler) restoreSong(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("RestoreSong handler")
+
+	var result models.Song
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	result, err := h.service.RestoreSong(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error restoring song: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	writer.Header().Set("ETag", etag(result.Version))
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// importSongs : Обработка запроса для пакетного импорта песен из CSV, JSON или NDJSON
+func (h *ApiHandler) importSongs(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("ImportSongs handler")
+
+	var result models.ImportReport
+	var rows []models.ImportRow
+	var format string
+	var dryRun bool
+	var err error
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	if value := request.URL.Query().Get("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Dry_run must be true or false", err), reqID)
+			return
+		}
+	}
+
+	format, err = importFormat(request.URL.Query().Get("format"), request.Header.Get("Content-Type"))
+	if err != nil {
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	rows, err = decodeImport(http.MaxBytesReader(writer, request.Body, maxImportSize), format)
+	if err != nil {
+		h.loger.Errorf("Error decoding import file: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.ImportSongs(request.Context(), rows, dryRun)
+	if err != nil {
+		h.loger.Errorf("Error importing songs: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// exportSongs : Обработка запроса для потоковой выгрузки песен в NDJSON или CSV
+func (h *ApiHandler) exportSongs(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("ExportSongs handler")
+
+	var sortOptions models.SortOptions
+	var filterOptions map[string]string
+	var format string
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	format, err := exportFormat(request.URL.Query().Get("format"))
+	if err != nil {
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	if options, ok := request.Context().Value("sort_options").(models.SortOptions); ok {
+		sortOptions = options
+	}
+
+	if options, ok := request.Context().Value("filter_options").(map[string]string); ok {
+		filterOptions = options
+	}
+
+	// Выгрузка каталога может идти дольше WriteTimeout сервера
+	if err = http.NewResponseController(writer).SetWriteDeadline(time.Time{}); err != nil {
+		h.loger.Warnf("Error disabling write deadline: %v", err)
+	}
+
+	export := newExportWriter(writer, format)
+	err = h.service.ExportSongs(request.Context(), sortOptions, filterOptions, export.Write)
+	if err != nil {
+		h.loger.Errorf("Error exporting songs: %v", err)
+		// После начала выгрузки статус уже отправлен, клиент увидит оборванный ответ
+		if !export.started {
+			h.JSONError(writer, err, reqID)
+		}
+		return
+	}
+
+	if err = export.Close(); err != nil {
+		h.loger.Errorf("Error finishing export: %v", err)
+	}
+}
+
+// getSongLyrics : Обработка запроса для получения текста песни, разобранного на части
+func (h *ApiHandler) getSongLyrics(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetSongLyrics handler")
+
+	var result models.SongLyricsResponse
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	result, err := h.service.GetSongLyrics(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error getting song lyrics: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// JSONError : Обработка ошибок в JSON формате. HTTP статус и код ответа определяются видом доменной ошибки,
+// остальные ошибки считаются внутренними и их текст не передается клиенту
+func (h *ApiHandler) JSONError(w http.ResponseWriter, error error, reqID string) {
 	h.loger.Debugln("JSON Error util")
 
 	var resp []byte
-	w.WriteHeader(code)
+	code, answer := errorResponse(error)
+	answer.RequestID = reqID
+
 	w.Header().Set("Content-Type", "application/json")
-	answer := models.ErrorResponse{
-		RequestID: reqID,
-		Error:     error,
-	}
-	resp, err = json.Marshal(answer)
+	w.WriteHeader(code)
+	resp, err := json.Marshal(answer)
 	if err != nil {
 		h.loger.Errorf("Error marshalling response: %v", err)
 		return
@@ -323,3 +753,41 @@ func (h *ApiHandler) JSONError(w http.ResponseWriter, error string, code int, re
 		return
 	}
 }
+
+// errorResponse : Определение HTTP статуса и тела ответа по виду ошибки
+func errorResponse(err error) (int, models.ErrorResponse) {
+	var domainErr *models.DomainError
+	if !errors.As(err, &domainErr) {
+		return http.StatusInternalServerError, models.ErrorResponse{
+			Code:  models.CodeInternal,
+			Error: "Internal server error",
+		}
+	}
+
+	code := http.StatusInternalServerError
+	switch {
+	case errors.Is(domainErr.Kind, models.ErrBadRequest):
+		code = http.StatusBadRequest
+	case errors.Is(domainErr.Kind, models.ErrValidation):
+		code = http.StatusUnprocessableEntity
+	case errors.Is(domainErr.Kind, models.ErrNotFound):
+		code = http.StatusNotFound
+	case errors.Is(domainErr.Kind, models.ErrConflict):
+		code = http.StatusConflict
+	case errors.Is(domainErr.Kind, models.ErrPreconditionFailed):
+		code = http.StatusPreconditionFailed
+	case errors.Is(domainErr.Kind, models.ErrUpstream):
+		code = http.StatusBadGateway
+	case errors.Is(domainErr.Kind, models.ErrUnauthorized):
+		code = http.StatusUnauthorized
+	case errors.Is(domainErr.Kind, models.ErrForbidden):
+		code = http.StatusForbidden
+	case errors.Is(domainErr.Kind, models.ErrTooManyRequests):
+		code = http.StatusTooManyRequests
+	}
+
+	return code, models.ErrorResponse{
+		Code:  domainErr.Code,
+		Error: domainErr.Message,
+	}
+}
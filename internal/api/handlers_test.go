@@ -0,0 +1,38 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestErrorResponse(t *testing.T) {
+	cases := []struct {
+		err    error
+		status int
+		code   string
+	}{
+		{models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "bad body", nil), http.StatusBadRequest, models.CodeInvalidBody},
+		{models.NewError(models.ErrValidation, models.CodeInvalidFilter, "bad filter", nil), http.StatusUnprocessableEntity, models.CodeInvalidFilter},
+		{models.NewError(models.ErrNotFound, models.CodeSongNotFound, "Song not found", nil), http.StatusNotFound, models.CodeSongNotFound},
+		{models.NewError(models.ErrConflict, models.CodeSongExists, "Song already exists", nil), http.StatusConflict, models.CodeSongExists},
+		{models.NewError(models.ErrPreconditionFailed, models.CodeVersionMismatch, "Song was modified by another request", nil), http.StatusPreconditionFailed, models.CodeVersionMismatch},
+		{models.NewError(models.ErrUpstream, models.CodeMusicInfoFailed, "upstream", nil), http.StatusBadGateway, models.CodeMusicInfoFailed},
+		{models.NewError(models.ErrUnauthorized, models.CodeUnauthorized, "Token is not valid", nil), http.StatusUnauthorized, models.CodeUnauthorized},
+		{models.NewError(models.ErrForbidden, models.CodeForbidden, "Role editor is required", nil), http.StatusForbidden, models.CodeForbidden},
+		{fmt.Errorf("wrapped: %w", models.NewError(models.ErrNotFound, models.CodeSongNotFound, "Song not found", nil)), http.StatusNotFound, models.CodeSongNotFound},
+		{errors.New("pq: connection refused"), http.StatusInternalServerError, models.CodeInternal},
+	}
+
+	for _, c := range cases {
+		status, response := errorResponse(c.err)
+		assert.Equal(t, c.status, status, c.err.Error())
+		assert.Equal(t, c.code, response.Code, c.err.Error())
+		assert.NotContains(t, response.Error, "connection refused")
+	}
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/a-h/respond"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"EffectiveMobile/internal/health"
+	"EffectiveMobile/internal/models"
+)
+
+// healthz : Обработка запроса проверки живости: процесс запущен и отвечает, зависимости не проверяются
+func (h *ApiHandler) healthz(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("Healthz handler")
+
+	respond.WithJSON(writer, map[string]string{"status": health.StatusUp}, http.StatusOK)
+}
+
+// readyz : Обработка запроса проверки готовности. 503 с результатом по каждой зависимости,
+// если хотя бы одна недоступна или приложение останавливается
+func (h *ApiHandler) readyz(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("Readyz handler")
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	result := models.ReadinessResponse{Status: health.StatusReady, Dependencies: map[string]models.DependencyStatus{}}
+	if h.health != nil {
+		result = h.health.Check(request.Context())
+	}
+
+	status := http.StatusOK
+	if result.Status != health.StatusReady {
+		h.loger.Warnf("Application is not ready: %v", result)
+		status = http.StatusServiceUnavailable
+	}
+	respond.WithJSON(writer, result, status)
+}
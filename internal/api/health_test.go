@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/health"
+	"EffectiveMobile/internal/models"
+)
+
+func readyz(t *testing.T, router http.Handler) (int, models.ReadinessResponse) {
+	t.Helper()
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+	var result models.ReadinessResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &result))
+	return recorder.Code, result
+}
+
+func TestHealthz(t *testing.T) {
+	router := NewHandler(nil, nil, nil, nil, zap.NewNop().Sugar()).InitRoutes()
+
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.JSONEq(t, `{"status":"up"}`, recorder.Body.String())
+
+	// Без проверок приложение всегда готово
+	status, result := readyz(t, router)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, health.StatusReady, result.Status)
+}
+
+func TestReadyz(t *testing.T) {
+	var dbErr error
+	checker := health.NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return dbErr })
+	router := NewHandler(nil, nil, nil, checker, zap.NewNop().Sugar()).InitRoutes()
+
+	status, result := readyz(t, router)
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, health.StatusUp, result.Dependencies["database"].Status)
+
+	dbErr = errors.New("connection refused")
+	status, result = readyz(t, router)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, health.StatusNotReady, result.Status)
+	assert.Equal(t, "connection refused", result.Dependencies["database"].Error)
+
+	// После начала остановки приложение не готово, даже если зависимости доступны
+	dbErr = nil
+	checker.Shutdown()
+	status, result = readyz(t, router)
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	assert.Equal(t, health.StatusShuttingDown, result.Status)
+}
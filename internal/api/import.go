@@ -0,0 +1,194 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"EffectiveMobile/internal/models"
+)
+
+const (
+	// maxImportSize : максимальный размер файла импорта
+	maxImportSize = 32 << 20
+	// maxFieldLength : длина колонок varchar(255) таблицы songs
+	maxFieldLength = 255
+)
+
+// uuidPattern : формат id песни в файле импорта
+var uuidPattern = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}$`)
+
+// importFormat : Формат файла импорта из параметра format или заголовка Content-Type
+func importFormat(format string, contentType string) (string, error) {
+	if format == "" {
+		mediaType, _, _ := mime.ParseMediaType(contentType)
+		switch mediaType {
+		case "text/csv":
+			format = "csv"
+		case "application/json":
+			format = "json"
+		case "application/x-ndjson", "application/ndjson", "application/jsonl":
+			format = "ndjson"
+		}
+	}
+
+	switch format {
+	case "csv", "json", "ndjson":
+		return format, nil
+	}
+	return "", models.NewError(models.ErrBadRequest, models.CodeUnsupportedFormat, "Import format must be csv, json or ndjson", nil)
+}
+
+// decodeImport : Разбор файла импорта. Ошибки отдельных строк попадают в ImportRow.Error,
+// ошибка возвращается, только если файл нельзя разобрать целиком
+func decodeImport(body io.Reader, format string) ([]models.ImportRow, error) {
+	switch format {
+	case "csv":
+		return decodeImportCSV(body)
+	case "json":
+		return decodeImportJSON(body)
+	default:
+		return decodeImportNDJSON(body)
+	}
+}
+
+// decodeImportCSV : Разбор CSV с заголовком из имен полей песни в JSON: song, group, releaseDate, text, link, id
+func decodeImportCSV(body io.Reader) ([]models.ImportRow, error) {
+	reader := csv.NewReader(body)
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "CSV header is missing", err)
+	}
+
+	fields := make([]func(*models.Song, string), len(header))
+	required := map[string]bool{"song": false, "group": false}
+	for i, name := range header {
+		name = strings.TrimSpace(name)
+		switch name {
+		case "id":
+			fields[i] = func(song *models.Song, value string) { song.ID = value }
+		case "song":
+			fields[i] = func(song *models.Song, value string) { song.Name = value }
+		case "group":
+			fields[i] = func(song *models.Song, value string) { song.Artist = value }
+		case "releaseDate":
+			fields[i] = func(song *models.Song, value string) { song.Release = value }
+		case "text":
+			fields[i] = func(song *models.Song, value string) { song.Text = value }
+		case "link":
+			fields[i] = func(song *models.Song, value string) { song.Link = value }
+		default:
+			return nil, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, fmt.Sprintf("Unknown CSV column %v", name), nil)
+		}
+		if _, ok := required[name]; ok {
+			required[name] = true
+		}
+	}
+	for name, found := range required {
+		if !found {
+			return nil, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, fmt.Sprintf("CSV column %v is required", name), nil)
+		}
+	}
+
+	var rows []models.ImportRow
+	for number := 1; ; number++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		row := models.ImportRow{Row: number}
+		switch {
+		case errors.Is(err, csv.ErrFieldCount):
+			row.Error = fmt.Sprintf("Row must have %v fields", len(header))
+		case err != nil:
+			return nil, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, fmt.Sprintf("CSV row %v is malformed", number), err)
+		default:
+			for i, value := range record {
+				fields[i](&row.Song, value)
+			}
+			row.Error = validateImportSong(row.Song)
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// decodeImportJSON : Разбор JSON массива песен
+func decodeImportJSON(body io.Reader) ([]models.ImportRow, error) {
+	var items []json.RawMessage
+	if err := json.NewDecoder(body).Decode(&items); err != nil {
+		return nil, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body must be a JSON array of songs", err)
+	}
+
+	rows := make([]models.ImportRow, 0, len(items))
+	for i, item := range items {
+		rows = append(rows, decodeImportItem(i+1, item))
+	}
+	return rows, nil
+}
+
+// decodeImportNDJSON : Разбор NDJSON, по одной песне на строку. Пустые строки пропускаются, но учитываются в нумерации
+func decodeImportNDJSON(body io.Reader) ([]models.ImportRow, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), maxImportSize)
+
+	var rows []models.ImportRow
+	for number := 1; scanner.Scan(); number++ {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		rows = append(rows, decodeImportItem(number, line))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Error reading NDJSON body", err)
+	}
+	return rows, nil
+}
+
+// decodeImportItem : Разбор и проверка одной песни в JSON
+func decodeImportItem(number int, item []byte) models.ImportRow {
+	row := models.ImportRow{Row: number}
+
+	decoder := json.NewDecoder(bytes.NewReader(item))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&row.Song); err != nil {
+		row.Error = "Song must be a JSON object with song fields"
+		return row
+	}
+
+	row.Error = validateImportSong(row.Song)
+	return row
+}
+
+// validateImportSong : Проверка песни из файла импорта, возвращает текст ошибки или пустую строку
+func validateImportSong(song models.Song) string {
+	if song.Name == "" || song.Artist == "" {
+		return "Song name, artist are required"
+	}
+	if utf8.RuneCountInString(song.Name) > maxFieldLength || utf8.RuneCountInString(song.Artist) > maxFieldLength || utf8.RuneCountInString(song.Link) > maxFieldLength {
+		return fmt.Sprintf("Song name, artist and link must be at most %v characters", maxFieldLength)
+	}
+	if song.ID != "" && !uuidPattern.MatchString(song.ID) {
+		return "Song id must be a UUID"
+	}
+
+	var domainErr *models.DomainError
+	if err := validateRelease(song.Release); errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	if err := validateLink(song.Link); errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	return ""
+}
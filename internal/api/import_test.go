@@ -0,0 +1,81 @@
+package api
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestImportFormat(t *testing.T) {
+	format, err := importFormat("", "text/csv; charset=utf-8")
+	require.NoError(t, err)
+	assert.Equal(t, "csv", format)
+
+	format, err = importFormat("ndjson", "application/json")
+	require.NoError(t, err)
+	assert.Equal(t, "ndjson", format)
+
+	_, err = importFormat("", "application/xml")
+	assert.ErrorIs(t, err, models.ErrBadRequest)
+}
+
+func TestDecodeImportCSV(t *testing.T) {
+	body := "song,group,releaseDate,link\n" +
+		"Supermassive Black Hole,Muse,16.07.2006,https://www.youtube.com/watch?v=Xsp3_a-PMTw\n" +
+		"Hysteria,,,\n" +
+		"Starlight,Muse,2006-09-04,\n" +
+		"Uprising,Muse\n"
+
+	rows, err := decodeImport(strings.NewReader(body), "csv")
+	require.NoError(t, err)
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, models.ImportRow{Row: 1, Song: models.Song{
+		Name:    "Supermassive Black Hole",
+		Artist:  "Muse",
+		Release: "16.07.2006",
+		Link:    "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	}}, rows[0])
+	assert.NotEmpty(t, rows[1].Error)
+	assert.NotEmpty(t, rows[2].Error)
+	assert.NotEmpty(t, rows[3].Error)
+}
+
+func TestDecodeImportCSVHeader(t *testing.T) {
+	for _, body := range []string{"", "song\nHysteria\n", "song,group,album\n"} {
+		_, err := decodeImport(strings.NewReader(body), "csv")
+		assert.ErrorIs(t, err, models.ErrBadRequest, body)
+	}
+}
+
+func TestDecodeImportJSON(t *testing.T) {
+	body := `[{"song": "Hysteria", "group": "Muse", "text": "It's bugging me"}, {"song": "Uprising", "album": "The Resistance"}, 42]`
+
+	rows, err := decodeImport(strings.NewReader(body), "json")
+	require.NoError(t, err)
+	require.Len(t, rows, 3)
+
+	assert.Equal(t, models.ImportRow{Row: 1, Song: models.Song{Name: "Hysteria", Artist: "Muse", Text: "It's bugging me"}}, rows[0])
+	assert.NotEmpty(t, rows[1].Error)
+	assert.NotEmpty(t, rows[2].Error)
+
+	_, err = decodeImport(strings.NewReader(`{"song": "Hysteria"}`), "json")
+	assert.ErrorIs(t, err, models.ErrBadRequest)
+}
+
+func TestDecodeImportNDJSON(t *testing.T) {
+	body := `{"id": "7b0c2a8e-4f0d-4d4e-9b8a-3c1f2d5e6a7b", "song": "Hysteria", "group": "Muse"}` + "\n\n" +
+		`{"id": "42", "song": "Uprising", "group": "Muse"}` + "\n"
+
+	rows, err := decodeImport(strings.NewReader(body), "ndjson")
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+
+	assert.Equal(t, models.ImportRow{Row: 1, Song: models.Song{ID: "7b0c2a8e-4f0d-4d4e-9b8a-3c1f2d5e6a7b", Name: "Hysteria", Artist: "Muse"}}, rows[0])
+	assert.Equal(t, 3, rows[1].Row)
+	assert.NotEmpty(t, rows[1].Error)
+}
@@ -6,10 +6,13 @@ import (
 	"strings"
 	"time"
 
+	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 	"github.com/urfave/negroni"
 
+	"EffectiveMobile/internal/metrics"
 	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
 )
 
 const (
@@ -17,8 +20,7 @@ const (
 	defaultSortOrder = "desc"
 	defaultLimit     = "10"
 	defaultOffset    = "0"
-	defaultPageToken = "0"
-	defaultСouplet   = "0"
+	defaultСouplet   = "1"
 )
 
 var (
@@ -35,31 +37,95 @@ var (
 		"release": "",
 		"text":    "",
 		"link":    "",
+		"album":   "",
 	}
 )
 
-func (api *ApiHandler) LogAPI(h http.Handler) http.Handler {
-	logFn := func(writer http.ResponseWriter, request *http.Request) {
-		start := time.Now()
-		uri := request.RequestURI
-		method := request.Method
-		reqID := middleware.GetReqID(request.Context())
-		lrw := negroni.NewResponseWriter(writer)
+// logResponseWriter : negroni.ResponseWriter, через который http.ResponseController
+// получает доступ к исходному writer, например для снятия WriteTimeout
+type logResponseWriter struct {
+	negroni.ResponseWriter
+	writer http.ResponseWriter
+}
+
+func (w logResponseWriter) Unwrap() http.ResponseWriter {
+	return w.writer
+}
+
+// requestLog : данные запроса, которые определяют внутренние middleware, для итоговой строки LogAPI
+type requestLog struct {
+	apiKeyID string
+	traceID  string
+}
+
+type contextKey string
+
+const requestLogKey contextKey = "request_log"
+
+// withRequestLog : Контекст с записью requestLog, которую заполняют внутренние middleware
+func withRequestLog(ctx context.Context, entry *requestLog) context.Context {
+	return context.WithValue(ctx, requestLogKey, entry)
+}
 
-		h.ServeHTTP(lrw, request)
+// requestLogFrom : Запись requestLog запроса, false - если LogAPI не вызывался
+func requestLogFrom(ctx context.Context) (*requestLog, bool) {
+	entry, ok := ctx.Value(requestLogKey).(*requestLog)
+	return entry, ok
+}
 
-		statusCode := lrw.Status()
-		duration := time.Since(start)
+// routePattern : Шаблон маршрута routes, который обработает запрос, пустая строка для неизвестного маршрута.
+// Шаблон известен до обработки запроса, поэтому им помечаются и запросы, отклоненные middleware
+func routePattern(routes chi.Routes, request *http.Request) string {
+	rctx := chi.NewRouteContext()
+	if !routes.Match(rctx, request.Method, request.URL.Path) {
+		return ""
+	}
+	return rctx.RoutePattern()
+}
 
-		api.loger.Debugln(
-			"RequestID:", reqID,
-			"statusCode:", statusCode,
-			"uri:", uri,
-			"method:", method,
-			"duration:", duration,
-		)
+// LogAPI : Итоговая строка лога и метрики каждого запроса к маршрутам routes
+func (api *ApiHandler) LogAPI(routes chi.Routes) func(http.Handler) http.Handler {
+	return func(h http.Handler) http.Handler {
+		logFn := func(writer http.ResponseWriter, request *http.Request) {
+			start := time.Now()
+			uri := request.RequestURI
+			method := request.Method
+			reqID := middleware.GetReqID(request.Context())
+			lrw := logResponseWriter{ResponseWriter: negroni.NewResponseWriter(writer), writer: writer}
+			entry := &requestLog{}
+
+			// Логгер запроса передается в сервис и хранилище через контекст
+			ctx := reqctx.WithLogger(request.Context(), api.loger.With("requestId", reqID))
+			ctx = withRequestLog(ctx, entry)
+
+			h.ServeHTTP(lrw, request.WithContext(ctx))
+
+			statusCode := lrw.Status()
+			duration := time.Since(start)
+
+			route := routePattern(routes, request)
+			if route == "" {
+				route = "unmatched"
+			}
+			metrics.ObserveHTTP(method, route, statusCode, duration)
+
+			fields := []interface{}{
+				"RequestID:", reqID,
+				"statusCode:", statusCode,
+				"uri:", uri,
+				"method:", method,
+				"duration:", duration,
+			}
+			if entry.apiKeyID != "" {
+				fields = append(fields, "apiKeyId:", entry.apiKeyID)
+			}
+			if entry.traceID != "" {
+				fields = append(fields, "traceId:", entry.traceID)
+			}
+			api.loger.Debugln(fields...)
+		}
+		return http.HandlerFunc(logFn)
 	}
-	return http.HandlerFunc(logFn)
 }
 
 func (api *ApiHandler) Sorting(handler http.Handler) http.Handler {
@@ -72,7 +138,7 @@ func (api *ApiHandler) Sorting(handler http.Handler) http.Handler {
 
 		if sortBy != "" {
 			if _, ok := sortList[sortBy]; !ok {
-				api.JSONError(writer, "Sort_by is not valid", http.StatusBadRequest, reqID)
+				api.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidSort, "Sort_by is not valid", nil), reqID)
 				return
 			}
 		} else {
@@ -81,7 +147,7 @@ func (api *ApiHandler) Sorting(handler http.Handler) http.Handler {
 
 		if sortOrder != "" {
 			if strings.ToLower(sortOrder) != "asc" && strings.ToLower(sortOrder) != "desc" {
-				api.JSONError(writer, "Sort_order must be asc or desc", http.StatusBadRequest, reqID)
+				api.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidSort, "Sort_order must be asc or desc", nil), reqID)
 				return
 			}
 		} else {
@@ -133,7 +199,10 @@ func (api *ApiHandler) Pagination(handler http.Handler) http.Handler {
 		if limit == "" {
 			limit = defaultLimit
 		}
-		if pageToken == "" && offset == "" {
+		// Токен страницы имеет приоритет над смещением
+		if pageToken != "" {
+			offset = ""
+		} else if offset == "" {
 			offset = defaultOffset
 		}
 
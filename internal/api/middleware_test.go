@@ -0,0 +1,40 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestMetricsRoute(t *testing.T) {
+	router := NewHandler(nil, nil, nil, nil, zap.NewNop().Sugar()).InitRoutes()
+
+	// Неизвестный маршрут учитывается без пути запроса
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/api/unknown/123", nil))
+	assert.Equal(t, http.StatusNotFound, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `music_store_http_requests_total{method="GET",route="unmatched",status="404"}`)
+	assert.NotContains(t, recorder.Body.String(), "/api/unknown/123")
+}
+
+func TestMetricsRouteRejected(t *testing.T) {
+	router := NewHandler(nil, nil, nil, nil, zap.NewNop().Sugar()).InitRoutes()
+
+	// Запрос, отклоненный до обработчика, учитывается по своему маршруту
+	recorder := httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodDelete, "/api/song/123", nil))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	recorder = httptest.NewRecorder()
+	router.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `music_store_http_requests_total{method="DELETE",route="/api/song/{id}",status="401"}`)
+}
@@ -0,0 +1,117 @@
+package api
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"EffectiveMobile/internal/models"
+)
+
+// playlistContentTypes : типы содержимого выгрузки плейлиста по формату
+var playlistContentTypes = map[string]string{
+	"m3u8": "application/vnd.apple.mpegurl",
+	"xspf": "application/xspf+xml",
+}
+
+// playlistFormat : Проверка формата выгрузки плейлиста, по умолчанию M3U8
+func playlistFormat(format string) (string, error) {
+	if format == "" {
+		return "m3u8", nil
+	}
+	if _, ok := playlistContentTypes[format]; ok {
+		return format, nil
+	}
+	return "", models.NewError(models.ErrBadRequest, models.CodeUnsupportedFormat, "Playlist format must be m3u8 or xspf", nil)
+}
+
+// writePlaylist : Отправка плейлиста файлом в формате format
+func writePlaylist(writer http.ResponseWriter, playlist models.Playlist, format string) error {
+	writer.Header().Set("Content-Type", playlistContentTypes[format])
+	writer.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="playlist-%v.%v"`, playlist.ID, format))
+	writer.WriteHeader(http.StatusOK)
+
+	if format == "xspf" {
+		return writeXSPF(writer, playlist)
+	}
+	return writeM3U8(writer, playlist)
+}
+
+// m3uLine : Значение в одну строку, перевод строки в M3U начинает новую запись
+var m3uLine = strings.NewReplacer("\r\n", " ", "\r", " ", "\n", " ")
+
+// writeM3U8 : Запись плейлиста в формате расширенного M3U в UTF-8.
+// Записи без ссылки на песню пропускаются, проигрывать в них нечего
+func writeM3U8(writer io.Writer, playlist models.Playlist) error {
+	var builder strings.Builder
+
+	builder.WriteString("#EXTM3U\n")
+	builder.WriteString("#PLAYLIST:" + m3uLine.Replace(playlist.Name) + "\n")
+	for _, entry := range playlist.Entries {
+		if entry.Song.Link == "" {
+			continue
+		}
+		builder.WriteString("#EXTINF:-1," + m3uLine.Replace(entry.Song.Artist+" - "+entry.Song.Name) + "\n")
+		builder.WriteString(m3uLine.Replace(entry.Song.Link) + "\n")
+	}
+
+	_, err := io.WriteString(writer, builder.String())
+	return err
+}
+
+// xspfPlaylist : корневой элемент XSPF (https://xspf.org/spec)
+type xspfPlaylist struct {
+	XMLName    xml.Name      `xml:"http://xspf.org/ns/0/ playlist"`
+	Version    string        `xml:"version,attr"`
+	Title      string        `xml:"title"`
+	Annotation string        `xml:"annotation,omitempty"`
+	Creator    string        `xml:"creator,omitempty"`
+	TrackList  xspfTrackList `xml:"trackList"`
+}
+
+// xspfTrackList : список треков обязателен в XSPF, даже пустой
+type xspfTrackList struct {
+	Tracks []xspfTrack `xml:"track"`
+}
+
+type xspfTrack struct {
+	Location string `xml:"location"`
+	Title    string `xml:"title"`
+	Creator  string `xml:"creator"`
+	TrackNum int    `xml:"trackNum"`
+}
+
+// writeXSPF : Запись плейлиста в формате XSPF. Как и в M3U8, записи без ссылки пропускаются,
+// trackNum сохраняет позицию записи в плейлисте
+func writeXSPF(writer io.Writer, playlist models.Playlist) error {
+	result := xspfPlaylist{
+		Version:    "1",
+		Title:      playlist.Name,
+		Annotation: playlist.Description,
+		Creator:    playlist.Owner,
+	}
+	for _, entry := range playlist.Entries {
+		if entry.Song.Link == "" {
+			continue
+		}
+		result.TrackList.Tracks = append(result.TrackList.Tracks, xspfTrack{
+			Location: entry.Song.Link,
+			Title:    entry.Song.Name,
+			Creator:  entry.Song.Artist,
+			TrackNum: entry.Position,
+		})
+	}
+
+	if _, err := io.WriteString(writer, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(writer)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(result); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, "\n")
+	return err
+}
@@ -0,0 +1,346 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/a-h/respond"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"EffectiveMobile/internal/models"
+)
+
+// decodePlaylist : Разбор и проверка тела запроса с плейлистом
+func decodePlaylist(body io.Reader) (playlist models.PlaylistRequest, err error) {
+	if err = json.NewDecoder(body).Decode(&playlist); err != nil {
+		return playlist, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err)
+	}
+
+	playlist.Name = strings.TrimSpace(playlist.Name)
+	if playlist.Name == "" {
+		return playlist, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Playlist name is required", nil)
+	}
+	if utf8.RuneCountInString(playlist.Name) > maxFieldLength {
+		return playlist, models.NewError(models.ErrBadRequest, models.CodeInvalidValue, fmt.Sprintf("Playlist name must be at most %v characters", maxFieldLength), nil)
+	}
+	return playlist, nil
+}
+
+// parsePosition : Разбор позиции записи плейлиста, позиции нумеруются с 1
+func parsePosition(value string) (int, error) {
+	position, err := strconv.Atoi(value)
+	if err != nil || position < 1 {
+		return 0, models.NewError(models.ErrBadRequest, models.CodeInvalidPosition, "Position must be a positive number", err)
+	}
+	return position, nil
+}
+
+// createPlaylist : Обработка запроса для создания плейлиста
+func (h *ApiHandler) createPlaylist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("CreatePlaylist handler")
+
+	var result models.Playlist
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	playlist, err := decodePlaylist(request.Body)
+	if err != nil {
+		h.loger.Errorf("Error decoding playlist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.CreatePlaylist(request.Context(), playlist)
+	if err != nil {
+		h.loger.Errorf("Error creating playlist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusCreated)
+}
+
+// readPlaylist : Обработка запроса для получения плейлиста с записями по его ID
+func (h *ApiHandler) readPlaylist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("ReadPlaylist handler")
+
+	var result models.Playlist
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	result, err := h.service.GetPlaylist(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error getting playlist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// getPlaylistsList : Обработка запроса для получения списка плейлистов
+func (h *ApiHandler) getPlaylistsList(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("GetPlaylistsList handler")
+
+	var result models.PlaylistsListResponse
+	var paginationOptions models.PaginationOptions
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	if options, ok := request.Context().Value("pagination_options").(models.PaginationOptions); ok {
+		paginationOptions = options
+	}
+	// Список плейлистов поддерживает только смещение
+	if paginationOptions.Offset == "" {
+		paginationOptions.Offset = defaultOffset
+	}
+
+	result, err := h.service.GetPlaylistsList(request.Context(), paginationOptions)
+	if err != nil {
+		h.loger.Errorf("Error getting playlists list: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// updatePlaylist : Обработка запроса для изменения названия и описания плейлиста
+func (h *ApiHandler) updatePlaylist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("UpdatePlaylist handler")
+
+	var result models.Playlist
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	playlist, err := decodePlaylist(request.Body)
+	if err != nil {
+		h.loger.Errorf("Error decoding playlist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.UpdatePlaylist(request.Context(), guid, playlist)
+	if err != nil {
+		h.loger.Errorf("Error updating playlist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// deletePlaylist : Обработка запроса для удаления плейлиста
+func (h *ApiHandler) deletePlaylist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("DeletePlaylist handler")
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	err := h.service.DeletePlaylist(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error deleting playlist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, models.PlaylistResponse{ID: guid}, http.StatusOK)
+}
+
+// addPlaylistEntry : Обработка запроса для вставки песни в плейлист
+func (h *ApiHandler) addPlaylistEntry(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("AddPlaylistEntry handler")
+
+	var entry models.PlaylistEntryRequest
+	var result models.Playlist
+	var err error
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	if err = json.NewDecoder(request.Body).Decode(&entry); err != nil {
+		h.loger.Errorf("Error unmarshalling request body: %v", err)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err), reqID)
+		return
+	}
+
+	if entry.SongID == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Entry song ID is required", nil), reqID)
+		return
+	}
+	if entry.Position < 0 {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidPosition, "Position must be a positive number or 0 to append", nil), reqID)
+		return
+	}
+
+	result, err = h.service.AddPlaylistEntry(request.Context(), guid, entry)
+	if err != nil {
+		h.loger.Errorf("Error adding playlist entry: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusCreated)
+}
+
+// removePlaylistEntry : Обработка запроса для удаления записи плейлиста по позиции
+func (h *ApiHandler) removePlaylistEntry(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("RemovePlaylistEntry handler")
+
+	var result models.Playlist
+	var position int
+	var err error
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	if position, err = parsePosition(chi.URLParam(request, "position")); err != nil {
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	result, err = h.service.RemovePlaylistEntry(request.Context(), guid, position)
+	if err != nil {
+		h.loger.Errorf("Error removing playlist entry: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// movePlaylistEntry : Обработка запроса для перемещения записи плейлиста на другую позицию
+func (h *ApiHandler) movePlaylistEntry(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("MovePlaylistEntry handler")
+
+	var move models.PlaylistMoveRequest
+	var result models.Playlist
+	var from int
+	var err error
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	if from, err = parsePosition(chi.URLParam(request, "position")); err != nil {
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	if err = json.NewDecoder(request.Body).Decode(&move); err != nil {
+		h.loger.Errorf("Error unmarshalling request body: %v", err)
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Request body is not valid JSON", err), reqID)
+		return
+	}
+
+	if move.Position < 1 {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidPosition, "Position must be a positive number", nil), reqID)
+		return
+	}
+
+	result, err = h.service.MovePlaylistEntry(request.Context(), guid, from, move.Position)
+	if err != nil {
+		h.loger.Errorf("Error moving playlist entry: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	respond.WithJSON(writer, result, http.StatusOK)
+}
+
+// exportPlaylist : Обработка запроса для выгрузки плейлиста в M3U8 или XSPF
+func (h *ApiHandler) exportPlaylist(writer http.ResponseWriter, request *http.Request) {
+	h.loger.Debugln("ExportPlaylist handler")
+
+	var playlist models.Playlist
+	var format string
+
+	reqID := middleware.GetReqID(request.Context())
+	uri := request.RequestURI
+	method := request.Method
+	h.loger.Debugf("RequestID: %v uri: %v method: %v", reqID, uri, method)
+
+	guid := chi.URLParam(request, "id")
+	if guid == "" {
+		h.JSONError(writer, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "ID is required", nil), reqID)
+		return
+	}
+
+	format, err := playlistFormat(request.URL.Query().Get("format"))
+	if err != nil {
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	playlist, err = h.service.GetPlaylist(request.Context(), guid)
+	if err != nil {
+		h.loger.Errorf("Error getting playlist: %v", err)
+		h.JSONError(writer, err, reqID)
+		return
+	}
+
+	if err = writePlaylist(writer, playlist, format); err != nil {
+		h.loger.Errorf("Error exporting playlist: %v", err)
+	}
+}
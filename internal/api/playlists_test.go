@@ -0,0 +1,109 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+var exportPlaylist = models.Playlist{
+	ID:   "5d1e8a3c-2b7f-4c9e-8a6d-1f0e3b2c4d5a",
+	Name: "Road\ntrip",
+	Entries: []models.PlaylistEntry{
+		{Position: 1, Song: models.Song{Name: "Hysteria", Artist: "Muse", Link: "https://example.com/hysteria.mp3"}},
+		{Position: 2, Song: models.Song{Name: "Untitled", Artist: "Muse"}},
+		{Position: 3, Song: models.Song{Name: "Rock & Roll", Artist: "Led Zeppelin", Link: "https://example.com/rock?a=1&b=2"}},
+	},
+}
+
+func TestDecodePlaylist(t *testing.T) {
+	playlist, err := decodePlaylist(strings.NewReader(`{"name": " Road trip ", "description": "Long drive"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "Road trip", playlist.Name)
+	assert.Equal(t, "Long drive", playlist.Description)
+
+	for _, body := range []string{``, `[]`, `{}`, `{"name": "  "}`, `{"name": "` + strings.Repeat("ы", maxFieldLength+1) + `"}`} {
+		_, err = decodePlaylist(strings.NewReader(body))
+		var domainErr *models.DomainError
+		require.True(t, errors.As(err, &domainErr), body)
+		assert.Equal(t, models.ErrBadRequest, domainErr.Kind, body)
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	position, err := parsePosition("3")
+	require.NoError(t, err)
+	assert.Equal(t, 3, position)
+
+	for _, value := range []string{"", "0", "-1", "x"} {
+		_, err = parsePosition(value)
+		assert.ErrorIs(t, err, models.ErrBadRequest, value)
+	}
+}
+
+func TestPlaylistFormat(t *testing.T) {
+	format, err := playlistFormat("")
+	require.NoError(t, err)
+	assert.Equal(t, "m3u8", format)
+
+	format, err = playlistFormat("xspf")
+	require.NoError(t, err)
+	assert.Equal(t, "xspf", format)
+
+	_, err = playlistFormat("pls")
+	assert.ErrorIs(t, err, models.ErrBadRequest)
+}
+
+func TestWritePlaylistM3U8(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	require.NoError(t, writePlaylist(recorder, exportPlaylist, "m3u8"))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/vnd.apple.mpegurl", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `attachment; filename="playlist-5d1e8a3c-2b7f-4c9e-8a6d-1f0e3b2c4d5a.m3u8"`, recorder.Header().Get("Content-Disposition"))
+	assert.Equal(t, "#EXTM3U\n"+
+		"#PLAYLIST:Road trip\n"+
+		"#EXTINF:-1,Muse - Hysteria\n"+
+		"https://example.com/hysteria.mp3\n"+
+		"#EXTINF:-1,Led Zeppelin - Rock & Roll\n"+
+		"https://example.com/rock?a=1&b=2\n",
+		recorder.Body.String())
+}
+
+func TestWritePlaylistXSPF(t *testing.T) {
+	recorder := httptest.NewRecorder()
+	require.NoError(t, writePlaylist(recorder, exportPlaylist, "xspf"))
+
+	assert.Equal(t, "application/xspf+xml", recorder.Header().Get("Content-Type"))
+	assert.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<playlist xmlns="http://xspf.org/ns/0/" version="1">
+  <title>Road&#xA;trip</title>
+  <trackList>
+    <track>
+      <location>https://example.com/hysteria.mp3</location>
+      <title>Hysteria</title>
+      <creator>Muse</creator>
+      <trackNum>1</trackNum>
+    </track>
+    <track>
+      <location>https://example.com/rock?a=1&amp;b=2</location>
+      <title>Rock &amp; Roll</title>
+      <creator>Led Zeppelin</creator>
+      <trackNum>3</trackNum>
+    </track>
+  </trackList>
+</playlist>
+`, recorder.Body.String())
+
+	// Пустой плейлист - корректный XSPF с пустым trackList
+	var builder strings.Builder
+	require.NoError(t, writeXSPF(&builder, models.Playlist{Name: "Empty"}))
+	assert.Contains(t, builder.String(), "<trackList></trackList>")
+}
@@ -0,0 +1,132 @@
+package api
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/ratelimit"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// bulkRoutes : маршруты массовых операций с отдельным, более строгим лимитом
+var bulkRoutes = map[string]bool{
+	"/api/songs/import": true,
+	"/api/songs/export": true,
+}
+
+// routeGroup : Группа лимита маршрута: массовые операции, чтение или изменения
+func routeGroup(method string, pattern string) ratelimit.Group {
+	if bulkRoutes[pattern] {
+		return ratelimit.GroupBulk
+	}
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ratelimit.GroupReads
+	}
+	return ratelimit.GroupWrites
+}
+
+// rateLimitClient : Клиент, которому принадлежит корзина: ключ API, пользователь токена или IP адрес
+func rateLimitClient(request *http.Request) string {
+	if principal, ok := auth.FromContext(request.Context()); ok {
+		if principal.KeyID != "" {
+			return "key:" + principal.KeyID
+		}
+		return "user:" + principal.Subject
+	}
+	return clientIP(request)
+}
+
+// clientIP : Корзина анонимного клиента по IP адресу
+func clientIP(request *http.Request) string {
+	host, _, err := net.SplitHostPort(request.RemoteAddr)
+	if err != nil {
+		host = request.RemoteAddr
+	}
+	return "ip:" + host
+}
+
+// seconds : Длительность в целых секундах с округлением вверх для заголовков
+func seconds(duration time.Duration) string {
+	return strconv.Itoa(int(math.Ceil(duration.Seconds())))
+}
+
+// RateLimit : Ограничение частоты запросов клиента к маршрутам routes по группам: чтение, изменения и массовые операции.
+// Ответ содержит заголовки RateLimit-*, отклоненный запрос получает 429 с Retry-After
+func (h *ApiHandler) RateLimit(routes chi.Routes) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if h.limiter == nil {
+				handler.ServeHTTP(writer, request)
+				return
+			}
+
+			rctx := chi.NewRouteContext()
+			if !routes.Match(rctx, request.Method, request.URL.Path) {
+				handler.ServeHTTP(writer, request)
+				return
+			}
+
+			group := routeGroup(request.Method, rctx.RoutePattern())
+			if h.takeRateLimit(writer, request, group, rateLimitClient(request)) {
+				handler.ServeHTTP(writer, request)
+			}
+		})
+	}
+}
+
+// takeRateLimit : Списание запроса клиента client из корзины группы group с заголовками RateLimit-*.
+// Возвращает false, если лимит исчерпан и клиенту уже отправлен ответ 429
+func (h *ApiHandler) takeRateLimit(writer http.ResponseWriter, request *http.Request, group ratelimit.Group, client string) bool {
+	result, err := h.limiter.Take(request.Context(), group, client)
+	if err != nil {
+		// Недоступность общего хранилища лимитов не должна останавливать API
+		reqctx.Logger(request.Context(), h.loger).Warnf("Rate limit store error: %v", err)
+		return true
+	}
+	if result.Limit == 0 {
+		return true
+	}
+
+	limit := h.limiter.Limit(group)
+	header := writer.Header()
+	header.Set("RateLimit-Policy", fmt.Sprintf("%v;w=%v", limit.Requests, seconds(limit.Period)))
+	header.Set("RateLimit-Limit", strconv.Itoa(result.Limit))
+	header.Set("RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	header.Set("RateLimit-Reset", seconds(result.Reset))
+
+	if !result.Allowed {
+		header.Set("Retry-After", seconds(result.RetryAfter))
+		reqID := middleware.GetReqID(request.Context())
+		message := fmt.Sprintf("Rate limit for %v is exceeded, retry in %v seconds", group, seconds(result.RetryAfter))
+		h.JSONError(writer, models.NewError(models.ErrTooManyRequests, models.CodeRateLimited, message, nil), reqID)
+		return false
+	}
+	return true
+}
+
+// documentRateLimits : Ответ 429 для всех маршрутов, описанный той же моделью, что и остальные ошибки
+func documentRateLimits(spec *openapi3.T) {
+	description := "Rate limit is exceeded; see the Retry-After and RateLimit-* headers"
+	for _, item := range spec.Paths.Map() {
+		for _, operation := range item.Operations() {
+			errorResponse := operation.Responses.Status(http.StatusInternalServerError)
+			if errorResponse == nil || errorResponse.Value == nil {
+				continue
+			}
+			response := *errorResponse.Value
+			response.Description = &description
+			operation.Responses.Set(strconv.Itoa(http.StatusTooManyRequests), &openapi3.ResponseRef{Value: &response})
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/ratelimit"
+)
+
+func TestRouteGroup(t *testing.T) {
+	assert.Equal(t, ratelimit.GroupReads, routeGroup(http.MethodGet, "/api/songs/"))
+	assert.Equal(t, ratelimit.GroupWrites, routeGroup(http.MethodPost, "/api/song"))
+	assert.Equal(t, ratelimit.GroupBulk, routeGroup(http.MethodPost, "/api/songs/import"))
+	assert.Equal(t, ratelimit.GroupBulk, routeGroup(http.MethodGet, "/api/songs/export"))
+}
+
+func TestRateLimitClient(t *testing.T) {
+	request := httptest.NewRequest(http.MethodGet, "/", nil)
+	request.RemoteAddr = "10.0.0.1:5000"
+	assert.Equal(t, "ip:10.0.0.1", rateLimitClient(request))
+
+	request = request.WithContext(auth.WithPrincipal(request.Context(), auth.Principal{Subject: "alice"}))
+	assert.Equal(t, "user:alice", rateLimitClient(request))
+
+	request = request.WithContext(auth.WithPrincipal(request.Context(), auth.Principal{Subject: "key:1", KeyID: "1"}))
+	assert.Equal(t, "key:1", rateLimitClient(request))
+}
+
+func TestRateLimit(t *testing.T) {
+	limiter := ratelimit.NewLimiter(ratelimit.Config{
+		Reads:  ratelimit.Limit{Requests: 2, Period: time.Minute},
+		Writes: ratelimit.Limit{Requests: 1, Period: time.Minute},
+	}, ratelimit.NewMemoryStore())
+	h := NewHandler(nil, nil, limiter, nil, zap.NewNop().Sugar())
+	ok := func(writer http.ResponseWriter, request *http.Request) {}
+
+	router := chi.NewRouter()
+	router.Use(h.RateLimit(router))
+	router.Get("/api/song/{id}", ok)
+	router.Delete("/api/song/{id}", ok)
+	router.Post("/api/songs/import", ok)
+
+	send := func(method string, path string, remoteAddr string) *httptest.ResponseRecorder {
+		request := httptest.NewRequest(method, path, nil)
+		request.RemoteAddr = remoteAddr
+		recorder := httptest.NewRecorder()
+		router.ServeHTTP(recorder, request)
+		return recorder
+	}
+
+	recorder := send(http.MethodGet, "/api/song/1", "10.0.0.1:5000")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "2;w=60", recorder.Header().Get("RateLimit-Policy"))
+	assert.Equal(t, "2", recorder.Header().Get("RateLimit-Limit"))
+	assert.Equal(t, "1", recorder.Header().Get("RateLimit-Remaining"))
+	assert.Equal(t, "30", recorder.Header().Get("RateLimit-Reset"))
+
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/song/1", "10.0.0.1:5000").Code)
+	recorder = send(http.MethodGet, "/api/song/1", "10.0.0.1:5000")
+	assert.Equal(t, http.StatusTooManyRequests, recorder.Code)
+	assert.Equal(t, "30", recorder.Header().Get("Retry-After"))
+	assert.Equal(t, "0", recorder.Header().Get("RateLimit-Remaining"))
+	assert.Contains(t, recorder.Body.String(), `"code":"rate_limited"`)
+
+	// Изменения и массовые операции считаются отдельно, массовые без лимита
+	assert.Equal(t, http.StatusOK, send(http.MethodDelete, "/api/song/1", "10.0.0.1:5000").Code)
+	assert.Equal(t, http.StatusTooManyRequests, send(http.MethodDelete, "/api/song/1", "10.0.0.1:5000").Code)
+	recorder = send(http.MethodPost, "/api/songs/import", "10.0.0.1:5000")
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Empty(t, recorder.Header().Get("RateLimit-Limit"))
+
+	// У другого клиента своя корзина
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, "/api/song/1", "10.0.0.2:5000").Code)
+}
+
+func TestDocumentRateLimits(t *testing.T) {
+	NewHandler(nil, nil, nil, nil, zap.NewNop().Sugar()).InitRoutes()
+
+	require.NotNil(t, spec.Paths.Value("/api/songs/").Get.Responses.Status(http.StatusTooManyRequests))
+	require.NotNil(t, spec.Paths.Value("/api/song").Post.Responses.Status(http.StatusTooManyRequests))
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"EffectiveMobile/internal/metrics"
 	"EffectiveMobile/internal/models"
 )
 
@@ -22,20 +23,74 @@ func (h *ApiHandler) InitRoutes() *chi.Mux {
 	router := chi.NewRouter()
 
 	router.Use(middleware.RequestID)
-	router.Use(h.LogAPI)
+	router.Use(h.LogAPI(router))
+	router.Use(h.Trace(router))
+	router.Use(h.Authenticate)
+	router.Use(h.RateLimit(router))
+	router.Use(h.Authorize(router))
 
 	router.Post("/api/song", h.createSong)
 	router.Put("/api/song", h.updateSong)
+	router.Patch("/api/song/{id}", h.patchSong)
 	router.Delete("/api/song/{id}", h.deleteSong)
 	router.Get("/api/song/info", h.getSongInfo)
 	router.Get("/api/song/{id}", h.readSong)
 	router.Get("/api/song/{id}/couplet", h.getSongCouplet)
+	router.Get("/api/song/{id}/lyrics", h.getSongLyrics)
+	router.Get("/api/song/{id}/revisions", h.getSongRevisions)
+	router.Get("/api/song/{id}/revisions/diff", h.diffSongRevisions)
+	router.Post("/api/song/{id}/revisions/{rev}/revert", h.revertSong)
+	router.Post("/api/song/{id}/restore", h.restoreSong)
+	router.With(h.Pagination).Get("/api/trash", h.getTrash)
 
 	router.Route("/api/songs", func(r chi.Router) {
 		r.Use(h.Sorting)
 		r.Use(h.Filtering)
 		r.Use(h.Pagination)
 		r.Get("/", h.getSongsList)
+		r.Get("/search", h.searchSongs)
+		r.Post("/import", h.importSongs)
+		r.Get("/export", h.exportSongs)
+	})
+
+	router.Route("/api/artists", func(r chi.Router) {
+		r.With(h.Pagination).Get("/", h.getArtistsList)
+		r.Post("/", h.createArtist)
+		r.Get("/{id}", h.readArtist)
+		r.Put("/{id}", h.updateArtist)
+		r.Delete("/{id}", h.deleteArtist)
+		r.With(h.Sorting, h.Filtering, h.Pagination).Get("/{id}/songs", h.getArtistSongs)
+	})
+
+	router.Route("/api/albums", func(r chi.Router) {
+		r.With(h.Pagination).Get("/", h.getAlbumsList)
+		r.Post("/", h.createAlbum)
+		r.Get("/{id}", h.readAlbum)
+		r.Put("/{id}", h.updateAlbum)
+		r.Delete("/{id}", h.deleteAlbum)
+		r.Put("/{id}/tracks", h.setAlbumTracks)
+		r.Post("/{id}/tracks", h.addAlbumTrack)
+	})
+
+	router.Route("/api/playlists", func(r chi.Router) {
+		r.With(h.Pagination).Get("/", h.getPlaylistsList)
+		r.Post("/", h.createPlaylist)
+		r.Get("/{id}", h.readPlaylist)
+		r.Put("/{id}", h.updatePlaylist)
+		r.Delete("/{id}", h.deletePlaylist)
+		r.Get("/{id}/export", h.exportPlaylist)
+		r.Post("/{id}/entries", h.addPlaylistEntry)
+		r.Patch("/{id}/entries/{position}", h.movePlaylistEntry)
+		r.Delete("/{id}/entries/{position}", h.removePlaylistEntry)
+	})
+
+	router.Get("/api/cache", h.getCacheStats)
+
+	router.Route("/api/keys", func(r chi.Router) {
+		r.With(h.Pagination).Get("/", h.getAPIKeysList)
+		r.Post("/", h.createAPIKey)
+		r.Delete("/{id}", h.revokeAPIKey)
+		r.Post("/{id}/rotate", h.rotateAPIKey)
 	})
 
 	// Create the API definition.
@@ -43,7 +98,7 @@ func (h *ApiHandler) InitRoutes() *chi.Mux {
 
 	// Create the routes and parameters of the Router in the REST API definition with an
 	// adapter, or do it manually.
-	err = chiadapter.Merge(api, router)
+	err := chiadapter.Merge(api, router)
 	if err != nil {
 		h.loger.Errorf("Failed to merge router to api: %v", err)
 	}
@@ -59,31 +114,132 @@ func (h *ApiHandler) InitRoutes() *chi.Mux {
 		HasRequestModel(rest.ModelOf[models.SongRequest]()).
 		HasResponseModel(http.StatusCreated, rest.ModelOf[models.SongResponse]()).
 		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusBadGateway, rest.ModelOf[models.ErrorResponse]()).
 		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
 
 	api.Get("/api/song/{id}").
+		HasDescription("Returns the song version in the ETag header; If-None-Match with the current ETag yields 304 Not Modified").
 		HasResponseModel(http.StatusOK, rest.ModelOf[models.Song]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
 		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
 
 	api.Put("/api/song").
+		HasDescription("If-Match with an ETag from GET /api/song/{id} makes the update conditional; a stale ETag yields 412").
 		HasRequestModel(rest.ModelOf[models.Song]()).
 		HasResponseModel(http.StatusOK, rest.ModelOf[models.Song]()).
 		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusPreconditionFailed, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Patch("/api/song/{id}").
+		HasDescription("If-Match with an ETag from GET /api/song/{id} makes the patch conditional; a stale ETag yields 412").
+		HasRequestModel(rest.ModelOf[models.Song]()).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Song]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusPreconditionFailed, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
 		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
 
 	api.Delete("/api/song/{id}").
+		HasDescription("Moves the song to the trash. If-Match with an ETag from GET /api/song/{id} makes the deletion conditional; a stale ETag yields 412").
 		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusPreconditionFailed, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
 		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
 
 	api.Get("/api/song/info").
 		HasRequestModel(rest.ModelOf[models.SongRequest]()).
 		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongInfoResponse]()).
 		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
 		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
 
 	api.Get("/api/song/{id}/couplet").
+		HasDescription("Couplets are numbered from 1; couplet_id accepts a number, a range like 2-4 or a list like 1,3. A couplet outside the song yields 404, a malformed couplet_id yields 422").
 		HasQueryParameter("couplet_id", rest.QueryParam{Type: "string", Required: false}).
 		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongVerseResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/song/{id}/lyrics").
+		HasDescription("Song text split into sections: stanzas are separated by blank lines or markers like [Chorus] and [Verse 2]; unmarked repeated stanzas are choruses").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongLyricsResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/song/{id}/revisions").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongRevisionsResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/song/{id}/revisions/diff").
+		HasQueryParameter("from", rest.QueryParam{Type: "integer", Required: true, Description: "revision number to compare from"}).
+		HasQueryParameter("to", rest.QueryParam{Type: "integer", Required: true, Description: "revision number to compare to"}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongRevisionDiff]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/song/{id}/revisions/{rev}/revert").
+		HasDescription("Restores the song fields from the revision; the revert is recorded as a new revision. Honors If-Match like PUT").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Song]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusPreconditionFailed, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/song/{id}/restore").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Song]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/trash").
+		HasDescription("Deleted songs, most recently deleted first; songs are purged after the retention period").
+		HasQueryParameter("limit", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("offset", rest.QueryParam{Type: "string", Required: false}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.TrashResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/songs/import").
+		HasDescription("Bulk import of songs. The body is CSV with a header of song field names, a JSON array or NDJSON; the format is taken from Content-Type unless format is set. Rows are reported as created, duplicate or invalid").
+		HasQueryParameter("format", rest.QueryParam{Type: "string", Required: false, Description: "csv, json or ndjson"}).
+		HasQueryParameter("dry_run", rest.QueryParam{Type: "boolean", Required: false, Description: "validate and report without saving"}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.ImportReport]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/songs/export").
+		HasDescription("Streams all songs matching the filters in the requested order; the CSV header matches the import format").
+		HasQueryParameter("format", rest.QueryParam{Type: "string", Required: false, Description: "ndjson (default) or csv"}).
+		HasQueryParameter("sort_by", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("sort_order", rest.QueryParam{Type: "string", Required: false, Description: "asc or desc"}).
+		HasQueryParameter("song", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("group", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("release", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("text", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("link", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("album", rest.QueryParam{Type: "string", Required: false, Description: "album ID; only songs that are tracks of the album"}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Song]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
 		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
 
 	api.Get("/api/songs/").
@@ -94,11 +250,246 @@ func (h *ApiHandler) InitRoutes() *chi.Mux {
 		HasQueryParameter("release", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
 		HasQueryParameter("text", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
 		HasQueryParameter("link", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("album", rest.QueryParam{Type: "string", Required: false, Description: "album ID; only songs that are tracks of the album"}).
+		HasQueryParameter("limit", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("offset", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("page_token", rest.QueryParam{Type: "string", Required: false, Description: "nextPageToken from the previous page; takes precedence over offset"}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongsListResponse]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/songs/search").
+		HasQueryParameter("q", rest.QueryParam{Type: "string", Required: true, Description: "search query in websearch_to_tsquery syntax"}).
+		HasQueryParameter("lang", rest.QueryParam{Type: "string", Required: false, Description: "ru or en; both configurations are used if empty"}).
+		HasQueryParameter("song", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("group", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("release", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("limit", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("offset", rest.QueryParam{Type: "string", Required: false}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongsSearchResponse]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/artists/").
+		HasDescription("Artist names are unique regardless of case and surrounding spaces").
+		HasRequestModel(rest.ModelOf[models.ArtistRequest]()).
+		HasResponseModel(http.StatusCreated, rest.ModelOf[models.Artist]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/artists/").
+		HasDescription("Artists ordered by name with the number of their songs").
+		HasQueryParameter("limit", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("offset", rest.QueryParam{Type: "string", Required: false}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.ArtistsListResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/artists/{id}").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Artist]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Put("/api/artists/{id}").
+		HasDescription("Renames the artist; the new name is applied to all of the artist's songs and recorded in their revisions").
+		HasRequestModel(rest.ModelOf[models.ArtistRequest]()).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Artist]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Delete("/api/artists/{id}").
+		HasDescription("Only artists without songs, including songs in the trash, can be deleted; otherwise 409 is returned").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.ArtistResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/artists/{id}/songs").
+		HasDescription("Songs of the artist with the same sorting, filters and pagination as /api/songs").
+		HasQueryParameter("sort_by", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("sort_order", rest.QueryParam{Type: "string", Required: false, Description: "asc or desc"}).
+		HasQueryParameter("song", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
+		HasQueryParameter("release", rest.QueryParam{Type: "string", Required: false, Description: "operator:value; supported operators: eq, nq, gt, gte, lt, lte, like, ilike"}).
 		HasQueryParameter("limit", rest.QueryParam{Type: "string", Required: false}).
 		HasQueryParameter("offset", rest.QueryParam{Type: "string", Required: false}).
-		HasQueryParameter("page_token", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("page_token", rest.QueryParam{Type: "string", Required: false, Description: "nextPageToken from the previous page; takes precedence over offset"}).
 		HasResponseModel(http.StatusOK, rest.ModelOf[models.SongsListResponse]()).
 		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/albums/").
+		HasDescription("Creates an album of an existing artist; tracks are song IDs in track order").
+		HasRequestModel(rest.ModelOf[models.AlbumRequest]()).
+		HasResponseModel(http.StatusCreated, rest.ModelOf[models.Album]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/albums/").
+		HasDescription("Albums ordered by title, without tracks").
+		HasQueryParameter("limit", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("offset", rest.QueryParam{Type: "string", Required: false}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.AlbumsListResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/albums/{id}").
+		HasDescription("Album with its tracks inline; songs in the trash are skipped and positions stay consecutive").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Album]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Put("/api/albums/{id}").
+		HasDescription("Updates the album; tracks are replaced only when the tracks field is present").
+		HasRequestModel(rest.ModelOf[models.AlbumRequest]()).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Album]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Delete("/api/albums/{id}").
+		HasDescription("Deletes the album and its track list; the songs are kept").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.AlbumResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Put("/api/albums/{id}/tracks").
+		HasDescription("Replaces the track list; the order of song IDs sets the track positions, so the same call reorders tracks").
+		HasRequestModel(rest.ModelOf[models.AlbumTracksRequest]()).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Album]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/albums/{id}/tracks").
+		HasDescription("Appends a song as the last track; a song already on the album yields 409").
+		HasRequestModel(rest.ModelOf[models.AlbumTrackRequest]()).
+		HasResponseModel(http.StatusCreated, rest.ModelOf[models.Album]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusConflict, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/playlists/").
+		HasDescription("Creates an empty playlist owned by the request author").
+		HasRequestModel(rest.ModelOf[models.PlaylistRequest]()).
+		HasResponseModel(http.StatusCreated, rest.ModelOf[models.Playlist]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/playlists/").
+		HasDescription("Playlists ordered by name, without entries").
+		HasQueryParameter("limit", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("offset", rest.QueryParam{Type: "string", Required: false}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.PlaylistsListResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/playlists/{id}").
+		HasDescription("Playlist with its entries and song data inline; entries of songs in the trash keep their position but are not returned").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Playlist]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Put("/api/playlists/{id}").
+		HasDescription("Updates the playlist name and description; entries are not changed").
+		HasRequestModel(rest.ModelOf[models.PlaylistRequest]()).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Playlist]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Delete("/api/playlists/{id}").
+		HasDescription("Deletes the playlist and its entries; the songs are kept").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.PlaylistResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/playlists/{id}/export").
+		HasDescription("Downloads the playlist as M3U8 (default) or XSPF; entries point at the song links, songs without a link are skipped").
+		HasQueryParameter("format", rest.QueryParam{Type: "string", Required: false, Description: "m3u8 or xspf"}).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/playlists/{id}/entries").
+		HasDescription("Inserts a song at the position, shifting the following entries down; position 0 or omitted appends. A song may appear more than once").
+		HasRequestModel(rest.ModelOf[models.PlaylistEntryRequest]()).
+		HasResponseModel(http.StatusCreated, rest.ModelOf[models.Playlist]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Patch("/api/playlists/{id}/entries/{position}").
+		HasDescription("Moves the entry to the position from the body; the entries in between shift by one").
+		HasRequestModel(rest.ModelOf[models.PlaylistMoveRequest]()).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Playlist]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Delete("/api/playlists/{id}/entries/{position}").
+		HasDescription("Removes the entry; the following entries move up so positions stay gap-free").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.Playlist]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/keys/").
+		HasDescription("Issues an API key for the X-API-Key header; the key is returned only in this response and stored hashed").
+		HasRequestModel(rest.ModelOf[models.APIKeyRequest]()).
+		HasResponseModel(http.StatusCreated, rest.ModelOf[models.APIKeyIssued]()).
+		HasResponseModel(http.StatusBadRequest, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/keys/").
+		HasDescription("API keys, newest first, including revoked and expired ones; keys are shown by prefix only").
+		HasQueryParameter("limit", rest.QueryParam{Type: "string", Required: false}).
+		HasQueryParameter("offset", rest.QueryParam{Type: "string", Required: false}).
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.APIKeysListResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Delete("/api/keys/{id}").
+		HasDescription("Revokes the API key; it stays in the list with the revocation time").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.APIKeyResponse]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Post("/api/keys/{id}/rotate").
+		HasDescription("Replaces the key with a new one keeping its scopes and expiry; the old key stops working at once. Revoked keys can not be rotated").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.APIKeyIssued]()).
+		HasResponseModel(http.StatusNotFound, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusUnprocessableEntity, rest.ModelOf[models.ErrorResponse]()).
+		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
+
+	api.Get("/api/cache").
+		HasDescription("Hit, miss and eviction counters of the song read cache since the start of this replica").
+		HasResponseModel(http.StatusOK, rest.ModelOf[models.CacheStatsResponse]()).
 		HasResponseModel(http.StatusInternalServerError, rest.ModelOf[models.ErrorResponse]())
 
 	// Create the spec.
@@ -107,6 +498,9 @@ func (h *ApiHandler) InitRoutes() *chi.Mux {
 		h.loger.Errorf("failed to create spec: %v", err)
 	}
 
+	documentSecurity(spec)
+	documentRateLimits(spec)
+
 	spec.Info.Version = "v1.0.0"
 	spec.Info.Description = "Описание интеграционных сервисов для работы с хранилищем песен"
 
@@ -117,6 +511,9 @@ func (h *ApiHandler) InitRoutes() *chi.Mux {
 		h.loger.Errorf("failed to create swagger UI handler: %v", err)
 	}
 	router.Handle("/swagger-ui*", ui)
+	router.Method(http.MethodGet, "/metrics", metrics.Handler())
+	router.Get("/healthz", h.healthz)
+	router.Get("/readyz", h.readyz)
 
 	return router
 }
@@ -0,0 +1,63 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+// Trace : Спан HTTP запроса к маршрутам routes, продолжающий трассировку из заголовка traceparent.
+// Спан называется по шаблону маршрута chi, найденному до обработки, поэтому имя получают
+// и запросы, отклоненные middleware. ID трассировки попадает в логи запроса
+func (h *ApiHandler) Trace(routes chi.Routes) func(http.Handler) http.Handler {
+	return func(handler http.Handler) http.Handler {
+		return http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			name := request.Method
+			attributes := []attribute.KeyValue{
+				semconv.HTTPRequestMethodKey.String(request.Method),
+				semconv.URLPath(request.URL.Path),
+				semconv.UserAgentOriginal(request.UserAgent()),
+			}
+			if route := routePattern(routes, request); route != "" {
+				name += " " + route
+				attributes = append(attributes, semconv.HTTPRoute(route))
+			}
+
+			ctx := otel.GetTextMapPropagator().Extract(request.Context(), propagation.HeaderCarrier(request.Header))
+			ctx, span := tracing.Start(ctx, name,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(attributes...))
+			defer span.End()
+
+			if span.SpanContext().IsValid() {
+				traceID := span.SpanContext().TraceID().String()
+				ctx = reqctx.WithLogger(ctx, reqctx.Logger(ctx, h.loger).With("traceId", traceID))
+				if entry, ok := requestLogFrom(ctx); ok {
+					entry.traceID = traceID
+				}
+			}
+
+			ww := middleware.NewWrapResponseWriter(writer, request.ProtoMajor)
+			handler.ServeHTTP(ww, request.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+		})
+	}
+}
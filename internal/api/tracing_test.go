@@ -0,0 +1,59 @@
+package api
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/tracing/tracingtest"
+)
+
+func TestTraceParent(t *testing.T) {
+	recorder := tracingtest.NewRecorder(t)
+	router := NewHandler(nil, nil, nil, nil, zap.NewNop().Sugar()).InitRoutes()
+
+	// Запрос продолжает трассировку клиента из заголовка traceparent
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), request)
+
+	spans := recorder.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /metrics", spans[0].Name)
+	assert.Equal(t, trace.SpanKindServer, spans[0].SpanKind)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+}
+
+func TestTraceRejectedRoute(t *testing.T) {
+	recorder := tracingtest.NewRecorder(t)
+	router := NewHandler(nil, nil, nil, nil, zap.NewNop().Sugar()).InitRoutes()
+
+	// Запрос, отклоненный до обработчика, получает имя маршрута
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, httptest.NewRequest(http.MethodDelete, "/api/song/123", nil))
+	assert.Equal(t, http.StatusUnauthorized, response.Code)
+
+	spans := recorder.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "DELETE /api/song/{id}", spans[0].Name)
+}
+
+func TestTraceRequestLog(t *testing.T) {
+	tracingtest.NewRecorder(t)
+	h := NewHandler(nil, nil, nil, nil, zap.NewNop().Sugar())
+	handler := h.Trace(chi.NewRouter())(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+
+	// ID трассировки попадает в итоговую строку лога запроса
+	entry := &requestLog{}
+	request := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	request.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), request.WithContext(withRequestLog(request.Context(), entry)))
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", entry.traceID)
+}
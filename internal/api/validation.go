@@ -0,0 +1,99 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"time"
+
+	"EffectiveMobile/internal/models"
+)
+
+// validateRelease : Проверка формата даты выхода песни
+func validateRelease(release string) error {
+	if release == "" {
+		return nil
+	}
+	if _, err := time.Parse("02.01.2006", release); err != nil {
+		return models.NewError(models.ErrValidation, models.CodeInvalidValue, "Release date must be in DD.MM.YYYY format", err)
+	}
+	return nil
+}
+
+// validateLink : Проверка, что ссылка является абсолютным http(s) адресом
+func validateLink(link string) error {
+	if link == "" {
+		return nil
+	}
+	u, err := url.ParseRequestURI(link)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return models.NewError(models.ErrValidation, models.CodeInvalidValue, "Link must be an absolute http or https URL", err)
+	}
+	return nil
+}
+
+// parseRevision : Разбор номера ревизии песни, ревизии нумеруются с 1
+func parseRevision(name string, value string) (int, error) {
+	revision, err := strconv.Atoi(value)
+	if err != nil || revision < 1 {
+		return 0, models.NewError(models.ErrBadRequest, models.CodeInvalidRevision, fmt.Sprintf("%v must be a positive revision number", name), err)
+	}
+	return revision, nil
+}
+
+// decodeSongPatch : Разбор тела запроса по правилам JSON Merge Patch (RFC 7396).
+// Отсутствующее поле не меняется, null очищает поле, id в патче должен совпадать с id песни
+func decodeSongPatch(body []byte, guid string) (patch models.SongPatch, err error) {
+	var members map[string]json.RawMessage
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	if err = decoder.Decode(&members); err != nil || members == nil {
+		return patch, models.NewError(models.ErrBadRequest, models.CodeInvalidBody, "Merge patch must be a JSON object", err)
+	}
+
+	fields := map[string]*models.PatchField{
+		"song":        &patch.Name,
+		"group":       &patch.Artist,
+		"releaseDate": &patch.Release,
+		"text":        &patch.Text,
+		"link":        &patch.Link,
+	}
+
+	for name, raw := range members {
+		if name == "id" {
+			var id string
+			if err = json.Unmarshal(raw, &id); err != nil || id != guid {
+				return patch, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, "Song id cannot be changed", err)
+			}
+			continue
+		}
+
+		field, ok := fields[name]
+		if !ok {
+			return patch, models.NewError(models.ErrBadRequest, models.CodeInvalidParameter, fmt.Sprintf("Unknown field %v", name), nil)
+		}
+
+		field.Set = true
+		if string(raw) == "null" {
+			field.Null = true
+			continue
+		}
+		if err = json.Unmarshal(raw, &field.Value); err != nil {
+			return patch, models.NewError(models.ErrValidation, models.CodeInvalidValue, fmt.Sprintf("Field %v must be a string or null", name), err)
+		}
+	}
+
+	if (patch.Name.Set && (patch.Name.Null || patch.Name.Value == "")) || (patch.Artist.Set && (patch.Artist.Null || patch.Artist.Value == "")) {
+		return patch, models.NewError(models.ErrValidation, models.CodeInvalidParameter, "Song name, artist cannot be cleared", nil)
+	}
+	if err = validateRelease(patch.Release.Value); err != nil {
+		return patch, err
+	}
+	if err = validateLink(patch.Link.Value); err != nil {
+		return patch, err
+	}
+
+	return patch, nil
+}
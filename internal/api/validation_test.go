@@ -0,0 +1,52 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+const patchSongID = "7b0c2a8e-4f0d-4d4e-9b8a-3c1f2d5e6a7b"
+
+func TestDecodeSongPatch(t *testing.T) {
+	patch, err := decodeSongPatch([]byte(`{"link": "https://www.youtube.com/watch?v=Xsp3_a-PMTw", "text": null}`), patchSongID)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.SongPatch{
+		Link: models.PatchField{Set: true, Value: "https://www.youtube.com/watch?v=Xsp3_a-PMTw"},
+		Text: models.PatchField{Set: true, Null: true},
+	}, patch)
+}
+
+func TestDecodeSongPatchInvalid(t *testing.T) {
+	cases := map[string]error{
+		`[]`:                            models.ErrBadRequest,
+		`null`:                          models.ErrBadRequest,
+		`{"song": null}`:                models.ErrValidation,
+		`{"group": ""}`:                 models.ErrValidation,
+		`{"releaseDate": "2006-07-16"}`: models.ErrValidation,
+		`{"link": "not a link"}`:        models.ErrValidation,
+		`{"text": 42}`:                  models.ErrValidation,
+		`{"album": "Black Holes and Revelations"}`:       models.ErrBadRequest,
+		`{"id": "00000000-0000-0000-0000-000000000000"}`: models.ErrBadRequest,
+	}
+
+	for body, want := range cases {
+		_, err := decodeSongPatch([]byte(body), patchSongID)
+		assert.ErrorIs(t, err, want, body)
+	}
+}
+
+func TestParseRevision(t *testing.T) {
+	revision, err := parseRevision("rev", "3")
+	require.NoError(t, err)
+	assert.Equal(t, 3, revision)
+
+	for _, value := range []string{"", "0", "-1", "first"} {
+		_, err = parseRevision("rev", value)
+		assert.ErrorIs(t, err, models.ErrBadRequest, value)
+	}
+}
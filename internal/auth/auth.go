@@ -0,0 +1,83 @@
+// Package auth проверяет JWT токены клиентов API и определяет роль, которую дает токен или ключ API
+package auth
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrInvalidToken : токен не прошел проверку подписи, срока действия, издателя или аудитории
+var ErrInvalidToken = errors.New("token is not valid")
+
+// Role : роль клиента API. Роли упорядочены, старшая роль включает права младших
+type Role int
+
+const (
+	// RoleNone : анонимный клиент, доступны только публичные маршруты
+	RoleNone Role = iota
+	RoleReader
+	RoleEditor
+	RoleAdmin
+)
+
+var roleNames = map[string]Role{
+	"reader": RoleReader,
+	"editor": RoleEditor,
+	"admin":  RoleAdmin,
+}
+
+// ParseRole : Роль по названию из токена или области действия ключа API
+func ParseRole(name string) (Role, bool) {
+	role, ok := roleNames[name]
+	return role, ok
+}
+
+// HighestRole : Старшая из известных ролей names, неизвестные названия пропускаются
+func HighestRole(names []string) Role {
+	highest := RoleNone
+	for _, name := range names {
+		if role, ok := ParseRole(name); ok && role > highest {
+			highest = role
+		}
+	}
+	return highest
+}
+
+func (r Role) String() string {
+	for name, role := range roleNames {
+		if role == r {
+			return name
+		}
+	}
+	return "none"
+}
+
+// Allows : Дает ли роль доступ к маршруту, требующему роль required
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// Principal : проверенный клиент API
+type Principal struct {
+	// Subject : идентификатор клиента, попадает в журнал ревизий как автор изменений
+	Subject string
+	// Role : старшая из ролей клиента
+	Role Role
+	// KeyID : ID ключа API, если клиент вошел по ключу, а не по токену
+	KeyID string
+}
+
+type contextKey string
+
+const principalKey contextKey = "principal"
+
+// WithPrincipal : Сохранение проверенного клиента в контексте запроса
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, principalKey, principal)
+}
+
+// FromContext : Проверенный клиент из контекста, false для анонимного запроса
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(principalKey).(Principal)
+	return principal, ok
+}
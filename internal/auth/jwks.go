@@ -0,0 +1,89 @@
+package auth
+
+import (
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+// jwk : ключ JWKS (RFC 7517). Поддерживаются RSA ключи для RS256 и симметричные (oct) для HS256
+type jwk struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	K   string `json:"k"`
+}
+
+// keySet : ключи проверки подписи по kid
+type keySet struct {
+	rsa  map[string]*rsa.PublicKey
+	hmac map[string][]byte
+}
+
+// loadJWKS : Чтение ключей из локального JWKS файла. Ключи шифрования (use=enc) пропускаются
+func loadJWKS(path string) (keySet, error) {
+	keys := keySet{
+		rsa:  make(map[string]*rsa.PublicKey),
+		hmac: make(map[string][]byte),
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return keys, fmt.Errorf("reading JWKS file: %w", err)
+	}
+
+	var set struct {
+		Keys []jwk `json:"keys"`
+	}
+	if err = json.Unmarshal(data, &set); err != nil {
+		return keys, fmt.Errorf("decoding JWKS file: %w", err)
+	}
+
+	for i, key := range set.Keys {
+		if key.Use != "" && key.Use != "sig" {
+			continue
+		}
+		switch key.Kty {
+		case "RSA":
+			publicKey, err := key.rsaPublicKey()
+			if err != nil {
+				return keys, fmt.Errorf("JWKS key %v: %w", i, err)
+			}
+			keys.rsa[key.Kid] = publicKey
+		case "oct":
+			secret, err := base64.RawURLEncoding.DecodeString(key.K)
+			if err != nil || len(secret) == 0 {
+				return keys, fmt.Errorf("JWKS key %v: k is not valid base64url", i)
+			}
+			keys.hmac[key.Kid] = secret
+		}
+	}
+
+	if len(keys.rsa) == 0 && len(keys.hmac) == 0 {
+		return keys, fmt.Errorf("JWKS file %v has no signing keys", path)
+	}
+	return keys, nil
+}
+
+// rsaPublicKey : RSA ключ из модуля n и экспоненты e в base64url
+func (key jwk) rsaPublicKey() (*rsa.PublicKey, error) {
+	n, err := base64.RawURLEncoding.DecodeString(key.N)
+	if err != nil || len(n) == 0 {
+		return nil, fmt.Errorf("n is not valid base64url")
+	}
+	e, err := base64.RawURLEncoding.DecodeString(key.E)
+	if err != nil || len(e) == 0 || len(e) > 4 {
+		return nil, fmt.Errorf("e is not valid base64url")
+	}
+
+	exponent := 0
+	for _, b := range e {
+		exponent = exponent<<8 | int(b)
+	}
+	return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, nil
+}
@@ -0,0 +1,113 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// leeway : допустимое расхождение часов с издателем токенов
+const leeway = 30 * time.Second
+
+type Config struct {
+	// Secret : общий ключ HS256 токенов без kid
+	Secret []byte
+	// JWKSFile : путь к локальному JWKS файлу с ключами RS256 и HS256
+	JWKSFile string
+	// Issuer, Audience : ожидаемые iss и aud токена, пустое значение не проверяется
+	Issuer   string
+	Audience string
+}
+
+// Enabled : Настроен ли хотя бы один ключ проверки подписи
+func (c Config) Enabled() bool {
+	return len(c.Secret) > 0 || c.JWKSFile != ""
+}
+
+// claims : поля токена. Роли передаются массивом roles
+type claims struct {
+	Roles []string `json:"roles"`
+	jwt.RegisteredClaims
+}
+
+type Verifier struct {
+	secret []byte
+	keys   keySet
+	parser *jwt.Parser
+}
+
+func NewVerifier(config Config) (*Verifier, error) {
+	verifier := &Verifier{
+		secret: config.Secret,
+	}
+
+	if config.JWKSFile != "" {
+		keys, err := loadJWKS(config.JWKSFile)
+		if err != nil {
+			return nil, err
+		}
+		verifier.keys = keys
+	}
+
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg(), jwt.SigningMethodRS256.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(leeway),
+	}
+	if config.Issuer != "" {
+		options = append(options, jwt.WithIssuer(config.Issuer))
+	}
+	if config.Audience != "" {
+		options = append(options, jwt.WithAudience(config.Audience))
+	}
+	verifier.parser = jwt.NewParser(options...)
+
+	return verifier, nil
+}
+
+// Verify : Проверка токена и определение клиента. Неизвестные роли пропускаются,
+// токен без известных ролей дает только публичный доступ
+func (v *Verifier) Verify(token string) (Principal, error) {
+	var principal Principal
+	var tokenClaims claims
+
+	if _, err := v.parser.ParseWithClaims(token, &tokenClaims, v.key); err != nil {
+		return principal, fmt.Errorf("%w: %w", ErrInvalidToken, err)
+	}
+	if tokenClaims.Subject == "" {
+		return principal, fmt.Errorf("%w: sub is required", ErrInvalidToken)
+	}
+
+	principal.Subject = tokenClaims.Subject
+	principal.Role = HighestRole(tokenClaims.Roles)
+	return principal, nil
+}
+
+// key : Ключ проверки подписи по алгоритму и kid из заголовка токена.
+// Тип ключа определяется алгоритмом, поэтому RSA ключ не может быть использован как секрет HS256
+func (v *Verifier) key(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	switch token.Method.Alg() {
+	case jwt.SigningMethodHS256.Alg():
+		if secret, ok := v.keys.hmac[kid]; ok {
+			return secret, nil
+		}
+		if kid == "" && len(v.secret) > 0 {
+			return v.secret, nil
+		}
+	case jwt.SigningMethodRS256.Alg():
+		if publicKey, ok := v.keys.rsa[kid]; ok {
+			return publicKey, nil
+		}
+		// Без kid подходит единственный RSA ключ
+		if kid == "" && len(v.keys.rsa) == 1 {
+			for _, publicKey := range v.keys.rsa {
+				return publicKey, nil
+			}
+		}
+	}
+	return nil, errors.New("no key for the token")
+}
@@ -0,0 +1,143 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSecret = []byte("0123456789abcdef0123456789abcdef")
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, kid string, claims jwt.MapClaims) string {
+	t.Helper()
+	token := jwt.NewWithClaims(method, claims)
+	if kid != "" {
+		token.Header["kid"] = kid
+	}
+	signed, err := token.SignedString(key)
+	require.NoError(t, err)
+	return signed
+}
+
+func validClaims(roles ...string) jwt.MapClaims {
+	return jwt.MapClaims{
+		"sub":   "alice",
+		"roles": roles,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}
+}
+
+// writeJWKS : JWKS файл с RSA ключом rsa-1 и симметричным ключом hmac-1
+func writeJWKS(t *testing.T, publicKey *rsa.PublicKey, secret []byte) string {
+	t.Helper()
+	set := map[string]any{"keys": []map[string]string{
+		{
+			"kty": "RSA",
+			"kid": "rsa-1",
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(publicKey.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(publicKey.E)).Bytes()),
+		},
+		{"kty": "oct", "kid": "hmac-1", "k": base64.RawURLEncoding.EncodeToString(secret)},
+		{"kty": "RSA", "kid": "enc-1", "use": "enc"},
+	}}
+	data, err := json.Marshal(set)
+	require.NoError(t, err)
+
+	path := filepath.Join(t.TempDir(), "jwks.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestVerifyHS256(t *testing.T) {
+	verifier, err := NewVerifier(Config{Secret: testSecret})
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(sign(t, jwt.SigningMethodHS256, testSecret, "", validClaims("reader", "editor", "unknown")))
+	require.NoError(t, err)
+	assert.Equal(t, Principal{Subject: "alice", Role: RoleEditor}, principal)
+
+	// Неверная подпись, истекший токен, токен без срока действия и без sub
+	expired := validClaims("admin")
+	expired["exp"] = time.Now().Add(-time.Hour).Unix()
+	noExpiry := validClaims("admin")
+	delete(noExpiry, "exp")
+	noSubject := validClaims("admin")
+	delete(noSubject, "sub")
+
+	for name, token := range map[string]string{
+		"signature": sign(t, jwt.SigningMethodHS256, []byte("another secret"), "", validClaims("admin")),
+		"expired":   sign(t, jwt.SigningMethodHS256, testSecret, "", expired),
+		"no exp":    sign(t, jwt.SigningMethodHS256, testSecret, "", noExpiry),
+		"no sub":    sign(t, jwt.SigningMethodHS256, testSecret, "", noSubject),
+		"none":      sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, "", validClaims("admin")),
+		"garbage":   "not.a.token",
+	} {
+		_, err = verifier.Verify(token)
+		assert.ErrorIs(t, err, ErrInvalidToken, name)
+	}
+}
+
+func TestVerifyJWKS(t *testing.T) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	jwksSecret := []byte("fedcba9876543210fedcba9876543210")
+
+	verifier, err := NewVerifier(Config{JWKSFile: writeJWKS(t, &privateKey.PublicKey, jwksSecret)})
+	require.NoError(t, err)
+
+	principal, err := verifier.Verify(sign(t, jwt.SigningMethodRS256, privateKey, "rsa-1", validClaims("admin")))
+	require.NoError(t, err)
+	assert.Equal(t, RoleAdmin, principal.Role)
+
+	// Единственный RSA ключ подходит и без kid
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodRS256, privateKey, "", validClaims("admin")))
+	require.NoError(t, err)
+
+	principal, err = verifier.Verify(sign(t, jwt.SigningMethodHS256, jwksSecret, "hmac-1", validClaims("reader")))
+	require.NoError(t, err)
+	assert.Equal(t, RoleReader, principal.Role)
+
+	// Неизвестный kid и подмена алгоритма: HS256 с публичным RSA ключом в качестве секрета
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodRS256, privateKey, "rsa-2", validClaims("admin")))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodHS256, privateKey.PublicKey.N.Bytes(), "rsa-1", validClaims("admin")))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = NewVerifier(Config{JWKSFile: filepath.Join(t.TempDir(), "missing.json")})
+	assert.Error(t, err)
+}
+
+func TestVerifyIssuerAudience(t *testing.T) {
+	verifier, err := NewVerifier(Config{Secret: testSecret, Issuer: "https://auth.example.com", Audience: "music-store"})
+	require.NoError(t, err)
+
+	claims := validClaims("editor")
+	claims["iss"] = "https://auth.example.com"
+	claims["aud"] = "music-store"
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodHS256, testSecret, "", claims))
+	require.NoError(t, err)
+
+	claims["aud"] = "another-service"
+	_, err = verifier.Verify(sign(t, jwt.SigningMethodHS256, testSecret, "", claims))
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
+
+func TestRole(t *testing.T) {
+	assert.True(t, RoleAdmin.Allows(RoleEditor))
+	assert.True(t, RoleEditor.Allows(RoleEditor))
+	assert.False(t, RoleReader.Allows(RoleEditor))
+	assert.True(t, RoleNone.Allows(RoleNone))
+	assert.Equal(t, "editor", RoleEditor.String())
+	assert.Equal(t, "none", RoleNone.String())
+}
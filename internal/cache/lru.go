@@ -0,0 +1,220 @@
+// Package cache реализует ограниченный по размеру LRU кэш со сроком жизни записей
+// и объединением одновременных загрузок одного ключа
+package cache
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Config : Size - наибольшее число записей, TTL - срок жизни записи, 0 - без ограничения срока.
+// LoadTimeout - ограничение времени загрузки в Load, 0 - без ограничения
+type Config struct {
+	Size        int
+	TTL         time.Duration
+	LoadTimeout time.Duration
+}
+
+// Stats : счетчики кэша с момента создания
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64
+	Size      int
+	Capacity  int
+}
+
+type entry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// call : загрузка ключа, которую ждут все одновременные промахи
+type call[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// LRU : кэш, вытесняющий давно не читавшиеся записи. Значения отдаются без копирования,
+// вызывающий код не должен их изменять
+type LRU[K comparable, V any] struct {
+	config Config
+	now    func() time.Time
+
+	mu    sync.Mutex
+	items map[K]*list.Element
+	order *list.List
+	loads map[K]*call[V]
+	// generation : меняется при каждой инвалидации, загрузка, начатая до нее, не сохраняется
+	generation uint64
+
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	evictions atomic.Uint64
+}
+
+func New[K comparable, V any](config Config) *LRU[K, V] {
+	return &LRU[K, V]{
+		config: config,
+		now:    time.Now,
+		items:  map[K]*list.Element{},
+		order:  list.New(),
+		loads:  map[K]*call[V]{},
+	}
+}
+
+// Get : Значение по ключу, просроченная запись удаляется
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	value, ok := c.get(key)
+	if ok {
+		c.hits.Add(1)
+	} else {
+		c.misses.Add(1)
+	}
+	return value, ok
+}
+
+func (c *LRU[K, V]) get(key K) (value V, ok bool) {
+	element, ok := c.items[key]
+	if !ok {
+		return value, false
+	}
+	item := element.Value.(*entry[K, V])
+	if !item.expires.IsZero() && !c.now().Before(item.expires) {
+		c.remove(element)
+		return value, false
+	}
+	c.order.MoveToFront(element)
+	return item.value, true
+}
+
+// Set : Сохранение значения, при переполнении вытесняется самая давно читавшаяся запись
+func (c *LRU[K, V]) Set(key K, value V) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(key, value)
+}
+
+func (c *LRU[K, V]) set(key K, value V) {
+	var expires time.Time
+	if c.config.TTL > 0 {
+		expires = c.now().Add(c.config.TTL)
+	}
+
+	if element, ok := c.items[key]; ok {
+		item := element.Value.(*entry[K, V])
+		item.value = value
+		item.expires = expires
+		c.order.MoveToFront(element)
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry[K, V]{key: key, value: value, expires: expires})
+	for c.order.Len() > c.config.Size {
+		c.remove(c.order.Back())
+		c.evictions.Add(1)
+	}
+}
+
+func (c *LRU[K, V]) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.items, element.Value.(*entry[K, V]).key)
+}
+
+// Load : Значение из кэша или из load при промахе. Одновременные промахи одного ключа
+// ждут одну загрузку. Загрузка не зависит от отмены ctx первого промаха и ограничена LoadTimeout,
+// каждый вызов перестает ждать ее при отмене своего ctx. Ошибки не кэшируются
+func (c *LRU[K, V]) Load(ctx context.Context, key K, load func(ctx context.Context) (V, error)) (V, error) {
+	c.mu.Lock()
+	if value, ok := c.get(key); ok {
+		c.mu.Unlock()
+		c.hits.Add(1)
+		return value, nil
+	}
+	c.misses.Add(1)
+	pending, ok := c.loads[key]
+	if !ok {
+		pending = &call[V]{done: make(chan struct{})}
+		c.loads[key] = pending
+		go c.load(ctx, key, pending, c.generation, load)
+	}
+	c.mu.Unlock()
+
+	select {
+	case <-pending.done:
+		return pending.value, pending.err
+	case <-ctx.Done():
+		var value V
+		return value, ctx.Err()
+	}
+}
+
+// load : Загрузка ключа для всех ожидающих промахов. Значение сохраняется, если с начала загрузки
+// не было инвалидации generation
+func (c *LRU[K, V]) load(ctx context.Context, key K, pending *call[V], generation uint64, load func(ctx context.Context) (V, error)) {
+	ctx = context.WithoutCancel(ctx)
+	if c.config.LoadTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.config.LoadTimeout)
+		defer cancel()
+	}
+
+	pending.value, pending.err = load(ctx)
+
+	c.mu.Lock()
+	if c.loads[key] == pending {
+		delete(c.loads, key)
+	}
+	if pending.err == nil && generation == c.generation {
+		c.set(key, pending.value)
+	}
+	c.mu.Unlock()
+	close(pending.done)
+}
+
+// Delete : Удаление записи. Загрузка ключа, начатая до удаления, не попадет в кэш,
+// а новые промахи не будут ее ждать
+func (c *LRU[K, V]) Delete(key K) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.items[key]; ok {
+		c.remove(element)
+	}
+	delete(c.loads, key)
+	c.generation++
+}
+
+// Purge : Удаление всех записей
+func (c *LRU[K, V]) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.items = map[K]*list.Element{}
+	c.order.Init()
+	c.loads = map[K]*call[V]{}
+	c.generation++
+}
+
+// Stats : Счетчики попаданий, промахов и вытеснений и текущий размер
+func (c *LRU[K, V]) Stats() Stats {
+	c.mu.Lock()
+	size := c.order.Len()
+	c.mu.Unlock()
+
+	return Stats{
+		Hits:      c.hits.Load(),
+		Misses:    c.misses.Load(),
+		Evictions: c.evictions.Load(),
+		Size:      size,
+		Capacity:  c.config.Size,
+	}
+}
@@ -0,0 +1,159 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLRUEviction(t *testing.T) {
+	c := New[string, int](Config{Size: 2})
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Чтение a делает самой давней запись b
+	_, ok := c.Get("a")
+	require.True(t, ok)
+	c.Set("c", 3)
+
+	_, ok = c.Get("b")
+	assert.False(t, ok)
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+
+	stats := c.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, uint64(1), stats.Evictions)
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, 2, stats.Capacity)
+}
+
+func TestLRUTTL(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	c := New[string, int](Config{Size: 10, TTL: time.Minute})
+	c.now = func() time.Time { return now }
+
+	c.Set("a", 1)
+	now = now.Add(59 * time.Second)
+	_, ok := c.Get("a")
+	assert.True(t, ok)
+
+	now = now.Add(time.Second)
+	_, ok = c.Get("a")
+	assert.False(t, ok)
+	assert.Zero(t, c.Stats().Size)
+}
+
+func TestLRULoad(t *testing.T) {
+	c := New[string, int](Config{Size: 10})
+	var loads atomic.Int32
+	release := make(chan struct{})
+
+	// Одновременные промахи ждут одну загрузку
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := c.Load(context.Background(), "a", func(context.Context) (int, error) {
+				loads.Add(1)
+				<-release
+				return 1, nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, 1, value)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	assert.Equal(t, int32(1), loads.Load())
+
+	value, err := c.Load(context.Background(), "a", func(context.Context) (int, error) { return 2, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 1, value)
+
+	// Ошибки не кэшируются
+	_, err = c.Load(context.Background(), "b", func(context.Context) (int, error) { return 0, errors.New("not found") })
+	assert.Error(t, err)
+	value, err = c.Load(context.Background(), "b", func(context.Context) (int, error) { return 2, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+}
+
+func TestLRULoadCanceled(t *testing.T) {
+	c := New[string, int](Config{Size: 10, LoadTimeout: time.Minute})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	loadErr := make(chan error, 1)
+
+	// Отмена первого промаха не прерывает загрузку, которую ждут остальные
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		_, err := c.Load(ctx, "a", func(ctx context.Context) (int, error) {
+			close(started)
+			<-release
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			return 1, ctx.Err()
+		})
+		loadErr <- err
+	}()
+	<-started
+	cancel()
+	assert.ErrorIs(t, <-loadErr, context.Canceled)
+
+	waiter := make(chan int)
+	go func() {
+		value, err := c.Load(context.Background(), "a", func(context.Context) (int, error) { return 2, nil })
+		assert.NoError(t, err)
+		waiter <- value
+	}()
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	assert.Equal(t, 1, <-waiter)
+
+	value, ok := c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 1, value)
+}
+
+func TestLRUDeleteDuringLoad(t *testing.T) {
+	c := New[string, int](Config{Size: 10})
+	started := make(chan struct{})
+	release := make(chan struct{})
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		value, err := c.Load(context.Background(), "a", func(context.Context) (int, error) {
+			close(started)
+			<-release
+			return 1, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, 1, value)
+	}()
+
+	// Запись изменилась, пока загружалось прежнее значение
+	<-started
+	c.Delete("a")
+	close(release)
+	<-done
+
+	value, err := c.Load(context.Background(), "a", func(context.Context) (int, error) { return 2, nil })
+	require.NoError(t, err)
+	assert.Equal(t, 2, value)
+
+	c.Purge()
+	_, ok := c.Get("a")
+	assert.False(t, ok)
+}
@@ -0,0 +1,233 @@
+// Package config собирает настройки приложения из значений по умолчанию, файла (YAML или .env),
+// переменных окружения и флагов командной строки и проверяет их до запуска
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+
+	"EffectiveMobile/internal/ratelimit"
+	"EffectiveMobile/internal/tracing"
+)
+
+// Server : адрес веб сервера и проверки готовности
+type Server struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	// ReadinessTimeout : ограничение времени каждой проверки /readyz
+	ReadinessTimeout time.Duration `yaml:"readinessTimeout"`
+	// ShutdownDelay : время между переходом /readyz в неготовое состояние и остановкой веб сервера
+	ShutdownDelay time.Duration `yaml:"shutdownDelay"`
+}
+
+// Database : подключение к PostgreSQL
+type Database struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	Name     string `yaml:"name"`
+	SSLMode  string `yaml:"sslMode"`
+	// QueryTimeout : ограничение времени одного вызова хранилища, 0 отключает ограничение
+	QueryTimeout time.Duration `yaml:"queryTimeout"`
+	// ImportTimeout : ограничение времени импорта песен вместо QueryTimeout, 0 отключает ограничение
+	ImportTimeout time.Duration `yaml:"importTimeout"`
+	// ImportBatchSize : число строк импорта в одном COPY
+	ImportBatchSize int `yaml:"importBatchSize"`
+}
+
+// Pool : размер пула соединений, 0 оставляет значения pgx по умолчанию
+type Pool struct {
+	MaxConns        int           `yaml:"maxConns"`
+	MinConns        int           `yaml:"minConns"`
+	MaxConnLifetime time.Duration `yaml:"maxConnLifetime"`
+	MaxConnIdleTime time.Duration `yaml:"maxConnIdleTime"`
+}
+
+// Log : уровень логов zap от -1 (debug) до 5 (fatal)
+type Log struct {
+	Level int `yaml:"level"`
+}
+
+// Enrichment : внешний API информации о песнях, пустой адрес отключает обогащение
+type Enrichment struct {
+	URL     string        `yaml:"url"`
+	Timeout time.Duration `yaml:"timeout"`
+	// ReadinessCheck : проверять доступность API в /readyz
+	ReadinessCheck bool `yaml:"readinessCheck"`
+}
+
+// Auth : проверка JWT токенов, пустые секрет и JWKS отключают авторизацию
+type Auth struct {
+	JWTSecret string `yaml:"jwtSecret"`
+	JWKSFile  string `yaml:"jwksFile"`
+	Issuer    string `yaml:"issuer"`
+	Audience  string `yaml:"audience"`
+}
+
+// Pagination : ключ подписи токенов страниц, пустой ключ случайный при каждом запуске
+type Pagination struct {
+	TokenSecret string `yaml:"tokenSecret"`
+}
+
+// Cache : кэш чтения песен, нулевой размер отключает кэш
+type Cache struct {
+	Size int           `yaml:"size"`
+	TTL  time.Duration `yaml:"ttl"`
+}
+
+// RateLimit : ограничения групп маршрутов в формате запросы/период, пустое значение или 0 снимает ограничение
+type RateLimit struct {
+	Reads  string `yaml:"reads"`
+	Writes string `yaml:"writes"`
+	Bulk   string `yaml:"bulk"`
+	// Auth : проверки ключей API с одного IP адреса
+	Auth string `yaml:"auth"`
+}
+
+// Tracing : экспорт спанов и доля трассируемых запросов
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`
+	SampleRatio float64 `yaml:"sampleRatio"`
+}
+
+// Trash : срок хранения удаленных песен, 0 хранит их всегда
+type Trash struct {
+	Retention     time.Duration `yaml:"retention"`
+	PurgeInterval time.Duration `yaml:"purgeInterval"`
+}
+
+type Config struct {
+	Server     Server     `yaml:"server"`
+	Database   Database   `yaml:"database"`
+	Pool       Pool       `yaml:"pool"`
+	Log        Log        `yaml:"log"`
+	Enrichment Enrichment `yaml:"enrichment"`
+	Auth       Auth       `yaml:"auth"`
+	Pagination Pagination `yaml:"pagination"`
+	Cache      Cache      `yaml:"cache"`
+	RateLimit  RateLimit  `yaml:"rateLimit"`
+	Tracing    Tracing    `yaml:"tracing"`
+	Trash      Trash      `yaml:"trash"`
+}
+
+// Default : Настройки по умолчанию для локального запуска
+func Default() Config {
+	return Config{
+		Server: Server{
+			Port:             8080,
+			ReadinessTimeout: 2 * time.Second,
+			ShutdownDelay:    2 * time.Second,
+		},
+		Database: Database{
+			Host:            "localhost",
+			Port:            5432,
+			User:            "postgres",
+			Name:            "postgres",
+			SSLMode:         "disable",
+			QueryTimeout:    5 * time.Second,
+			ImportTimeout:   5 * time.Minute,
+			ImportBatchSize: 1000,
+		},
+		Enrichment: Enrichment{
+			Timeout: 5 * time.Second,
+		},
+		Cache: Cache{
+			Size: 10000,
+			TTL:  time.Minute,
+		},
+		Tracing: Tracing{
+			Exporter:    tracing.ExporterNone,
+			SampleRatio: 1,
+		},
+		Trash: Trash{
+			Retention:     720 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+	}
+}
+
+// placeholderJWTSecret : секрет из прежнего примера .env, токены с ним может подписать кто угодно
+const placeholderJWTSecret = "local-development-secret-change-me"
+
+// sslModes : допустимые значения sslmode PostgreSQL
+var sslModes = map[string]bool{
+	"disable": true, "allow": true, "prefer": true, "require": true, "verify-ca": true, "verify-full": true,
+}
+
+// Validate : Проверка всех настроек сразу, ошибка перечисляет каждую неверную настройку
+func (c Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Server.Port > 0 && c.Server.Port <= 65535, "WEBSERVER_PORT must be between 1 and 65535, got %v", c.Server.Port)
+	check(c.Server.ReadinessTimeout > 0, "READINESS_TIMEOUT must be positive, got %v", c.Server.ReadinessTimeout)
+	check(c.Server.ShutdownDelay >= 0, "SHUTDOWN_DELAY must not be negative, got %v", c.Server.ShutdownDelay)
+
+	check(c.Database.Host != "", "POSTGRES_HOST is required")
+	check(c.Database.Port > 0 && c.Database.Port <= 65535, "POSTGRES_PORT must be between 1 and 65535, got %v", c.Database.Port)
+	check(c.Database.User != "", "POSTGRES_USER is required")
+	check(c.Database.Name != "", "POSTGRES_NAME is required")
+	check(sslModes[c.Database.SSLMode], "POSTGRES_SSLMODE %q is not a PostgreSQL sslmode", c.Database.SSLMode)
+	check(c.Database.QueryTimeout >= 0, "DB_QUERY_TIMEOUT must not be negative, got %v", c.Database.QueryTimeout)
+	check(c.Database.ImportTimeout >= 0, "DB_IMPORT_TIMEOUT must not be negative, got %v", c.Database.ImportTimeout)
+	check(c.Database.ImportBatchSize > 0, "DB_IMPORT_BATCH_SIZE must be positive, got %v", c.Database.ImportBatchSize)
+
+	check(c.Pool.MaxConns >= 0, "DB_POOL_MAX_CONNS must not be negative, got %v", c.Pool.MaxConns)
+	check(c.Pool.MinConns >= 0, "DB_POOL_MIN_CONNS must not be negative, got %v", c.Pool.MinConns)
+	check(c.Pool.MaxConns == 0 || c.Pool.MinConns <= c.Pool.MaxConns, "DB_POOL_MIN_CONNS %v is greater than DB_POOL_MAX_CONNS %v", c.Pool.MinConns, c.Pool.MaxConns)
+	check(c.Pool.MaxConnLifetime >= 0, "DB_POOL_MAX_CONN_LIFETIME must not be negative, got %v", c.Pool.MaxConnLifetime)
+	check(c.Pool.MaxConnIdleTime >= 0, "DB_POOL_MAX_CONN_IDLE_TIME must not be negative, got %v", c.Pool.MaxConnIdleTime)
+
+	check(c.Log.Level >= -1 && c.Log.Level <= 5, "LOGGER_LEVEL must be between -1 (debug) and 5 (fatal), got %v", c.Log.Level)
+
+	if c.Enrichment.URL != "" {
+		enrichmentURL, err := url.Parse(c.Enrichment.URL)
+		check(err == nil && (enrichmentURL.Scheme == "http" || enrichmentURL.Scheme == "https") && enrichmentURL.Host != "",
+			"ENRICHMENT_URL %q must be an absolute http or https URL", c.Enrichment.URL)
+		check(c.Enrichment.Timeout > 0, "ENRICHMENT_TIMEOUT must be positive, got %v", c.Enrichment.Timeout)
+	}
+	check(c.Enrichment.URL != "" || !c.Enrichment.ReadinessCheck, "ENRICHMENT_READINESS_CHECK requires ENRICHMENT_URL")
+
+	check(c.Cache.Size >= 0, "CACHE_SIZE must not be negative, got %v", c.Cache.Size)
+	check(c.Cache.TTL >= 0, "CACHE_TTL must not be negative, got %v", c.Cache.TTL)
+
+	check(c.Auth.JWTSecret != placeholderJWTSecret, "JWT_SECRET must not be the example value, set your own random secret")
+
+	for _, limit := range [][2]string{
+		{"RATE_LIMIT_READS", c.RateLimit.Reads},
+		{"RATE_LIMIT_WRITES", c.RateLimit.Writes},
+		{"RATE_LIMIT_BULK", c.RateLimit.Bulk},
+		{"RATE_LIMIT_AUTH", c.RateLimit.Auth},
+	} {
+		_, err := ratelimit.ParseLimit(limit[1])
+		check(err == nil, "%v is not valid: %v", limit[0], err)
+	}
+
+	switch c.Tracing.Exporter {
+	case "", tracing.ExporterNone, tracing.ExporterOTLP, tracing.ExporterStdout:
+	default:
+		errs = append(errs, fmt.Errorf("TRACING_EXPORTER %q must be otlp, stdout or none", c.Tracing.Exporter))
+	}
+	check(c.Tracing.SampleRatio >= 0 && c.Tracing.SampleRatio <= 1, "TRACING_SAMPLE_RATIO must be between 0 and 1, got %v", c.Tracing.SampleRatio)
+
+	check(c.Trash.Retention >= 0, "TRASH_RETENTION must not be negative, got %v", c.Trash.Retention)
+	check(c.Trash.Retention == 0 || c.Trash.PurgeInterval > 0, "TRASH_PURGE_INTERVAL must be positive, got %v", c.Trash.PurgeInterval)
+
+	return errors.Join(errs...)
+}
+
+// RateLimits : Ограничения групп маршрутов. Настройки должны пройти Validate
+func (c Config) RateLimits() ratelimit.Config {
+	reads, _ := ratelimit.ParseLimit(c.RateLimit.Reads)
+	writes, _ := ratelimit.ParseLimit(c.RateLimit.Writes)
+	bulk, _ := ratelimit.ParseLimit(c.RateLimit.Bulk)
+	authLimit, _ := ratelimit.ParseLimit(c.RateLimit.Auth)
+	return ratelimit.Config{Reads: reads, Writes: writes, Bulk: bulk, Auth: authLimit}
+}
@@ -0,0 +1,139 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// env : Переменные окружения теста вместо os.LookupEnv
+func env(values map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		value, ok := values[key]
+		return value, ok
+	}
+}
+
+func writeFile(t *testing.T, name string, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadDefaults(t *testing.T) {
+	config, args, err := Load(nil, env(map[string]string{"CONFIG_FILE": ""}))
+	require.NoError(t, err)
+	assert.Equal(t, Default(), config)
+	assert.Empty(t, args)
+}
+
+func TestLoadLayers(t *testing.T) {
+	file := writeFile(t, "app.env", "WEBSERVER_PORT=8081\nCACHE_SIZE=100\nCACHE_TTL=30s\nHOST_WEB_PORT=80\n")
+
+	// Файл переопределяет значения по умолчанию, окружение - файл, флаги - окружение
+	config, args, err := Load(
+		[]string{"-config", file, "-cache-size", "5", "migrate", "status"},
+		env(map[string]string{"CACHE_SIZE": "50", "CACHE_TTL": "10s"}),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, 8081, config.Server.Port)
+	assert.Equal(t, 10*time.Second, config.Cache.TTL)
+	assert.Equal(t, 5, config.Cache.Size)
+	assert.Equal(t, "localhost", config.Database.Host)
+	assert.Equal(t, []string{"migrate", "status"}, args)
+}
+
+func TestLoadYAML(t *testing.T) {
+	file := writeFile(t, "config.yaml", `
+server:
+  port: 9090
+database:
+  host: postgres
+  queryTimeout: 3s
+rateLimit:
+  reads: 100/1m
+enrichment:
+  url: http://music-info:8000
+  readinessCheck: true
+`)
+
+	config, _, err := Load(nil, env(map[string]string{"CONFIG_FILE": file, "POSTGRES_HOST": "db"}))
+	require.NoError(t, err)
+	assert.Equal(t, 9090, config.Server.Port)
+	assert.Equal(t, "db", config.Database.Host)
+	assert.Equal(t, 3*time.Second, config.Database.QueryTimeout)
+	assert.Equal(t, 100, config.RateLimits().Reads.Requests)
+	assert.True(t, config.Enrichment.ReadinessCheck)
+	assert.Equal(t, 5*time.Second, config.Enrichment.Timeout)
+
+	// Опечатка в названии настройки не пропускается молча
+	file = writeFile(t, "config.yml", "server:\n  prot: 9090\n")
+	_, _, err = Load([]string{"-config", file}, env(nil))
+	assert.Error(t, err)
+}
+
+func TestLoadFileMissing(t *testing.T) {
+	// Файл по умолчанию необязателен, указанный явно - обязателен
+	_, _, err := Load(nil, env(nil))
+	assert.NoError(t, err)
+
+	_, _, err = Load([]string{"-config", filepath.Join(t.TempDir(), "missing.yaml")}, env(nil))
+	assert.Error(t, err)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, _, err := Load(nil, env(map[string]string{"WEBSERVER_PORT": "http"}))
+	assert.ErrorContains(t, err, "WEBSERVER_PORT")
+
+	_, _, err = Load([]string{"-unknown-flag", "1"}, env(nil))
+	assert.Error(t, err)
+}
+
+func TestValidate(t *testing.T) {
+	assert.NoError(t, Default().Validate())
+
+	config := Default()
+	config.Server.Port = 0
+	config.Database.SSLMode = "maybe"
+	config.Pool.MaxConns = 2
+	config.Pool.MinConns = 4
+	config.RateLimit.Bulk = "30 per minute"
+	config.Tracing.SampleRatio = 2
+	config.Enrichment.URL = "music-info:8000"
+	config.Trash.PurgeInterval = 0
+	config.Database.ImportBatchSize = 0
+	config.Auth.JWTSecret = placeholderJWTSecret
+
+	err := config.Validate()
+	require.Error(t, err)
+	for _, name := range []string{"WEBSERVER_PORT", "POSTGRES_SSLMODE", "DB_POOL_MIN_CONNS", "RATE_LIMIT_BULK", "TRACING_SAMPLE_RATIO", "ENRICHMENT_URL", "TRASH_PURGE_INTERVAL", "DB_IMPORT_BATCH_SIZE", "JWT_SECRET"} {
+		assert.ErrorContains(t, err, name)
+	}
+}
+
+func TestPrint(t *testing.T) {
+	config := Default()
+	config.Database.Password = "qwerty"
+	config.Auth.JWTSecret = "local-development-secret"
+
+	var out bytes.Buffer
+	require.NoError(t, config.Print(&out))
+	assert.Contains(t, out.String(), "POSTGRES_PASSWORD="+redacted+"\n")
+	assert.Contains(t, out.String(), "JWT_SECRET="+redacted+"\n")
+	assert.Contains(t, out.String(), "PAGE_TOKEN_SECRET=\n")
+	assert.Contains(t, out.String(), "CACHE_TTL=1m0s\n")
+	assert.NotContains(t, out.String(), "qwerty")
+
+	// Вывод читается обратно как файл .env
+	file := writeFile(t, "printed.env", out.String())
+	loaded, _, err := Load([]string{"-config", file}, env(nil))
+	require.NoError(t, err)
+	assert.Equal(t, config.Cache, loaded.Cache)
+	assert.Equal(t, config.Trash, loaded.Trash)
+}
@@ -0,0 +1,232 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// defaultFile : файл настроек, который читается, если он есть, а другой файл не указан
+const defaultFile = ".env"
+
+// redacted : значение секретов в выводе настроек
+const redacted = "********"
+
+// setting : настройка с именем переменной окружения. Имя флага - то же имя в нижнем регистре через дефис
+type setting struct {
+	env    string
+	value  any
+	secret bool
+}
+
+// settings : Все настройки в порядке вывода, value указывает на поле c
+func (c *Config) settings() []setting {
+	return []setting{
+		{env: "LOGGER_LEVEL", value: &c.Log.Level},
+
+		{env: "WEBSERVER_HOST", value: &c.Server.Host},
+		{env: "WEBSERVER_PORT", value: &c.Server.Port},
+		{env: "READINESS_TIMEOUT", value: &c.Server.ReadinessTimeout},
+		{env: "SHUTDOWN_DELAY", value: &c.Server.ShutdownDelay},
+
+		{env: "POSTGRES_HOST", value: &c.Database.Host},
+		{env: "POSTGRES_PORT", value: &c.Database.Port},
+		{env: "POSTGRES_USER", value: &c.Database.User},
+		{env: "POSTGRES_PASSWORD", value: &c.Database.Password, secret: true},
+		{env: "POSTGRES_NAME", value: &c.Database.Name},
+		{env: "POSTGRES_SSLMODE", value: &c.Database.SSLMode},
+		{env: "DB_QUERY_TIMEOUT", value: &c.Database.QueryTimeout},
+		{env: "DB_IMPORT_TIMEOUT", value: &c.Database.ImportTimeout},
+		{env: "DB_IMPORT_BATCH_SIZE", value: &c.Database.ImportBatchSize},
+
+		{env: "DB_POOL_MAX_CONNS", value: &c.Pool.MaxConns},
+		{env: "DB_POOL_MIN_CONNS", value: &c.Pool.MinConns},
+		{env: "DB_POOL_MAX_CONN_LIFETIME", value: &c.Pool.MaxConnLifetime},
+		{env: "DB_POOL_MAX_CONN_IDLE_TIME", value: &c.Pool.MaxConnIdleTime},
+
+		{env: "PAGE_TOKEN_SECRET", value: &c.Pagination.TokenSecret, secret: true},
+
+		{env: "JWT_SECRET", value: &c.Auth.JWTSecret, secret: true},
+		{env: "JWT_JWKS_FILE", value: &c.Auth.JWKSFile},
+		{env: "JWT_ISSUER", value: &c.Auth.Issuer},
+		{env: "JWT_AUDIENCE", value: &c.Auth.Audience},
+
+		{env: "RATE_LIMIT_READS", value: &c.RateLimit.Reads},
+		{env: "RATE_LIMIT_WRITES", value: &c.RateLimit.Writes},
+		{env: "RATE_LIMIT_BULK", value: &c.RateLimit.Bulk},
+		{env: "RATE_LIMIT_AUTH", value: &c.RateLimit.Auth},
+
+		{env: "CACHE_SIZE", value: &c.Cache.Size},
+		{env: "CACHE_TTL", value: &c.Cache.TTL},
+
+		{env: "TRACING_EXPORTER", value: &c.Tracing.Exporter},
+		{env: "TRACING_SAMPLE_RATIO", value: &c.Tracing.SampleRatio},
+
+		{env: "TRASH_RETENTION", value: &c.Trash.Retention},
+		{env: "TRASH_PURGE_INTERVAL", value: &c.Trash.PurgeInterval},
+
+		{env: "ENRICHMENT_URL", value: &c.Enrichment.URL},
+		{env: "ENRICHMENT_TIMEOUT", value: &c.Enrichment.Timeout},
+		{env: "ENRICHMENT_READINESS_CHECK", value: &c.Enrichment.ReadinessCheck},
+	}
+}
+
+// flagName : Имя флага настройки: WEBSERVER_PORT - webserver-port
+func (s setting) flagName() string {
+	return strings.ReplaceAll(strings.ToLower(s.env), "_", "-")
+}
+
+// set : Разбор строкового значения настройки по типу поля
+func (s setting) set(raw string) error {
+	raw = strings.TrimSpace(raw)
+	var err error
+	switch value := s.value.(type) {
+	case *string:
+		*value = raw
+	case *int:
+		*value, err = strconv.Atoi(raw)
+	case *float64:
+		*value, err = strconv.ParseFloat(raw, 64)
+	case *bool:
+		*value, err = strconv.ParseBool(raw)
+	case *time.Duration:
+		*value, err = time.ParseDuration(raw)
+	default:
+		err = fmt.Errorf("unsupported type %T", s.value)
+	}
+	if err != nil {
+		return fmt.Errorf("%v %q is not valid: %w", s.env, raw, err)
+	}
+	return nil
+}
+
+// String : Значение настройки в формате, который принимает set
+func (s setting) String() string {
+	switch value := s.value.(type) {
+	case *string:
+		return *value
+	case *int:
+		return strconv.Itoa(*value)
+	case *float64:
+		return strconv.FormatFloat(*value, 'g', -1, 64)
+	case *bool:
+		return strconv.FormatBool(*value)
+	case *time.Duration:
+		return value.String()
+	}
+	return fmt.Sprint(s.value)
+}
+
+// Load : Сборка настроек по слоям: значения по умолчанию, файл, переменные окружения, флаги args.
+// Файл задается флагом -config или переменной CONFIG_FILE, иначе читается .env, если он есть.
+// lookupEnv - обычно os.LookupEnv. Возвращает проверенные настройки и аргументы после флагов
+func Load(args []string, lookupEnv func(string) (string, bool)) (Config, []string, error) {
+	config := Default()
+	settings := config.settings()
+
+	flags := flag.NewFlagSet("main", flag.ContinueOnError)
+	flags.SetOutput(io.Discard)
+	file := flags.String("config", "", "YAML or .env configuration file")
+	flagValues := map[string]string{}
+	for _, s := range settings {
+		flags.Func(s.flagName(), s.env, func(value string) error {
+			flagValues[s.env] = value
+			return nil
+		})
+	}
+	if err := flags.Parse(args); err != nil {
+		return config, nil, err
+	}
+
+	path, required := *file, true
+	if path == "" {
+		path, _ = lookupEnv("CONFIG_FILE")
+	}
+	if path == "" {
+		path, required = defaultFile, false
+	}
+	if err := config.loadFile(path, required); err != nil {
+		return config, nil, err
+	}
+
+	for _, s := range settings {
+		if value, ok := lookupEnv(s.env); ok {
+			if err := s.set(value); err != nil {
+				return config, nil, err
+			}
+		}
+	}
+
+	for _, s := range settings {
+		if value, ok := flagValues[s.env]; ok {
+			if err := s.set(value); err != nil {
+				return config, nil, err
+			}
+		}
+	}
+
+	if err := config.Validate(); err != nil {
+		return config, nil, err
+	}
+	return config, flags.Args(), nil
+}
+
+// loadFile : Чтение файла настроек: .yaml и .yml - секции Config, остальные - переменные в формате .env.
+// Отсутствие необязательного файла не ошибка
+func (c *Config) loadFile(path string, required bool) error {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) && !required {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("reading config file: %w", err)
+	}
+
+	switch filepath.Ext(path) {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err = decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("parsing config file %v: %w", path, err)
+		}
+		return nil
+	}
+
+	values, err := godotenv.Parse(bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("parsing config file %v: %w", path, err)
+	}
+	// Переменные, которые приложение не читает (например, порты docker-compose), пропускаются
+	for _, s := range c.settings() {
+		if value, ok := values[s.env]; ok {
+			if err = s.set(value); err != nil {
+				return fmt.Errorf("config file %v: %w", path, err)
+			}
+		}
+	}
+	return nil
+}
+
+// Print : Вывод действующих настроек в формате .env, секреты скрыты
+func (c Config) Print(w io.Writer) error {
+	for _, s := range c.settings() {
+		value := s.String()
+		if s.secret && value != "" {
+			value = redacted
+		}
+		if _, err := fmt.Fprintf(w, "%v=%v\n", s.env, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
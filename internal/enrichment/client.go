@@ -0,0 +1,136 @@
+package enrichment
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/metrics"
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/tracing"
+)
+
+var (
+	// ErrBadRequest : внешний API не принял запрос (ответ 400), например песня ему неизвестна
+	ErrBadRequest = errors.New("music info API: bad request")
+	// ErrUpstream : внешний API вернул ошибку сервера или некорректный ответ
+	ErrUpstream = errors.New("music info API: upstream error")
+	// ErrTimeout : внешний API не ответил за отведенное время
+	ErrTimeout = errors.New("music info API: timeout")
+)
+
+type Config struct {
+	URL     string
+	Timeout time.Duration
+}
+
+type Client struct {
+	baseURL string
+	http    *http.Client
+	loger   *zap.SugaredLogger
+}
+
+func NewClient(config Config, loger *zap.SugaredLogger) *Client {
+	return &Client{
+		baseURL: strings.TrimRight(config.URL, "/"),
+		http:    &http.Client{Timeout: config.Timeout},
+		loger:   loger,
+	}
+}
+
+// GetSongDetail : Получение информации о песне из внешнего API (GET /info?group=&song=)
+func (c *Client) GetSongDetail(ctx context.Context, group string, song string) (result models.SongDetail, err error) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "GET /info", trace.WithSpanKind(trace.SpanKindClient))
+	defer func() {
+		tracing.RecordError(span, err)
+		span.End()
+		metrics.ObserveEnrichment(outcome(err), time.Since(start))
+	}()
+
+	query := url.Values{}
+	query.Set("group", group)
+	query.Set("song", song)
+	infoURL := fmt.Sprintf("%s/info?%s", c.baseURL, query.Encode())
+
+	c.loger.Debugf("Requesting song detail: %v", infoURL)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, infoURL, nil)
+	if err != nil {
+		return result, fmt.Errorf("building request: %w", err)
+	}
+	request.Header.Set("Accept", "application/json")
+	// Внешний API продолжает трассировку запроса по заголовку traceparent
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(request.Header))
+	span.SetAttributes(semconv.HTTPRequestMethodKey.String(http.MethodGet), semconv.URLFull(infoURL), semconv.ServerAddress(request.URL.Hostname()))
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		var netErr interface{ Timeout() bool }
+		if errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout()) {
+			return result, fmt.Errorf("%w: %v", ErrTimeout, err)
+		}
+		return result, fmt.Errorf("%w: %v", ErrUpstream, err)
+	}
+	defer response.Body.Close()
+	span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+
+	switch {
+	case response.StatusCode == http.StatusOK:
+	case response.StatusCode == http.StatusBadRequest:
+		return result, ErrBadRequest
+	default:
+		return result, fmt.Errorf("%w: unexpected status %v", ErrUpstream, response.StatusCode)
+	}
+
+	if err = json.NewDecoder(response.Body).Decode(&result); err != nil {
+		return result, fmt.Errorf("%w: decoding response: %v", ErrUpstream, err)
+	}
+
+	c.loger.Debugf("Song detail: %v", result)
+	return result, nil
+}
+
+// Ping : Проверка доступности внешнего API для готовности приложения. Ответ 400 на запрос
+// без параметров означает, что API работает
+func (c *Client) Ping(ctx context.Context) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/info", nil)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+
+	response, err := c.http.Do(request)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUpstream, err)
+	}
+	response.Body.Close()
+
+	if response.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: unexpected status %v", ErrUpstream, response.StatusCode)
+	}
+	return nil
+}
+
+// outcome : Результат запроса к внешнему API для метрик
+func outcome(err error) string {
+	switch {
+	case err == nil:
+		return "success"
+	case errors.Is(err, ErrBadRequest):
+		return "bad_request"
+	case errors.Is(err, ErrTimeout):
+		return "timeout"
+	}
+	return "upstream_error"
+}
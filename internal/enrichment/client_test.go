@@ -0,0 +1,91 @@
+package enrichment
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/enrichment/enrichmenttest"
+	"EffectiveMobile/internal/tracing"
+	"EffectiveMobile/internal/tracing/tracingtest"
+)
+
+func newTestClient(t *testing.T, timeout time.Duration) *Client {
+	server := enrichmenttest.NewServer()
+	t.Cleanup(server.Close)
+
+	return NewClient(Config{URL: server.URL, Timeout: timeout}, zap.NewNop().Sugar())
+}
+
+func TestGetSongDetail(t *testing.T) {
+	client := newTestClient(t, time.Second)
+
+	detail, err := client.GetSongDetail(context.Background(), "Muse", "Supermassive Black Hole")
+	require.NoError(t, err)
+	assert.Equal(t, enrichmenttest.Songs[[2]string{"Muse", "Supermassive Black Hole"}], detail)
+}
+
+func TestGetSongDetailUnknownSong(t *testing.T) {
+	client := newTestClient(t, time.Second)
+
+	_, err := client.GetSongDetail(context.Background(), "Muse", "Unknown")
+	assert.ErrorIs(t, err, ErrBadRequest)
+}
+
+func TestGetSongDetailServerError(t *testing.T) {
+	client := newTestClient(t, time.Second)
+
+	_, err := client.GetSongDetail(context.Background(), enrichmenttest.FailGroup, "Song")
+	assert.ErrorIs(t, err, ErrUpstream)
+}
+
+func TestGetSongDetailTimeout(t *testing.T) {
+	client := newTestClient(t, enrichmenttest.SlowDelay/5)
+
+	_, err := client.GetSongDetail(context.Background(), enrichmenttest.SlowGroup, "Song")
+	assert.ErrorIs(t, err, ErrTimeout)
+}
+
+func TestOutcome(t *testing.T) {
+	assert.Equal(t, "success", outcome(nil))
+	assert.Equal(t, "bad_request", outcome(ErrBadRequest))
+	assert.Equal(t, "timeout", outcome(fmt.Errorf("%w: deadline", ErrTimeout)))
+	assert.Equal(t, "upstream_error", outcome(fmt.Errorf("%w: status 500", ErrUpstream)))
+}
+
+func TestGetSongDetailTraceParent(t *testing.T) {
+	tracingtest.NewRecorder(t)
+
+	var traceParent string
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		traceParent = request.Header.Get("traceparent")
+		_, _ = writer.Write([]byte(`{"releaseDate":"16.07.2006","text":"Ooh baby","link":"https://example.com"}`))
+	}))
+	t.Cleanup(server.Close)
+	client := NewClient(Config{URL: server.URL, Timeout: time.Second}, zap.NewNop().Sugar())
+
+	ctx, span := tracing.Start(context.Background(), "test")
+	defer span.End()
+	_, err := client.GetSongDetail(ctx, "Muse", "Supermassive Black Hole")
+	require.NoError(t, err)
+	assert.Contains(t, traceParent, span.SpanContext().TraceID().String())
+}
+
+func TestPing(t *testing.T) {
+	client := newTestClient(t, time.Second)
+	assert.NoError(t, client.Ping(context.Background()))
+
+	server := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(server.Close)
+	client = NewClient(Config{URL: server.URL, Timeout: time.Second}, zap.NewNop().Sugar())
+	assert.ErrorIs(t, client.Ping(context.Background()), ErrUpstream)
+}
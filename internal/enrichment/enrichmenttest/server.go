@@ -0,0 +1,59 @@
+// Package enrichmenttest содержит тестовую реализацию внешнего API информации о песнях (docs/sample.yaml)
+package enrichmenttest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"EffectiveMobile/internal/models"
+)
+
+const (
+	// FailGroup : группа, для которой сервер отвечает 500
+	FailGroup = "Internal Server Error"
+	// SlowGroup : группа, для которой сервер отвечает с задержкой SlowDelay
+	SlowGroup = "Slow Group"
+	SlowDelay = 500 * time.Millisecond
+)
+
+// Songs : известные серверу песни, ключ - группа и название песни
+var Songs = map[[2]string]models.SongDetail{
+	{"Muse", "Supermassive Black Hole"}: {
+		Release: "16.07.2006",
+		Text:    "Ooh baby, don't you know I suffer?\nOoh baby, can you hear me moan?\nYou caught me under false pretenses\nHow long before you let me go?\n\nOoh\nYou set my soul alight\nOoh\nYou set my soul alight",
+		Link:    "https://www.youtube.com/watch?v=Xsp3_a-PMTw",
+	},
+}
+
+// NewServer : Запуск тестового сервера по контракту GET /info?group=&song=
+func NewServer() *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info", func(writer http.ResponseWriter, request *http.Request) {
+		group := request.URL.Query().Get("group")
+		song := request.URL.Query().Get("song")
+
+		switch group {
+		case FailGroup:
+			writer.WriteHeader(http.StatusInternalServerError)
+			return
+		case SlowGroup:
+			select {
+			case <-time.After(SlowDelay):
+			case <-request.Context().Done():
+				return
+			}
+		}
+
+		detail, ok := Songs[[2]string{group, song}]
+		if group == "" || song == "" || !ok {
+			writer.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		writer.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(writer).Encode(detail)
+	})
+	return httptest.NewServer(mux)
+}
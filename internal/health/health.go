@@ -0,0 +1,103 @@
+// Package health проверяет готовность приложения принимать запросы: доступность базы данных,
+// версию схемы и внешние API
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"EffectiveMobile/internal/models"
+)
+
+const (
+	StatusUp           = "up"
+	StatusDown         = "down"
+	StatusReady        = "ready"
+	StatusNotReady     = "not_ready"
+	StatusShuttingDown = "shutting_down"
+)
+
+// Check : проверка одной зависимости, nil означает, что зависимость доступна
+type Check func(ctx context.Context) error
+
+type dependency struct {
+	name  string
+	check Check
+}
+
+// Checker : набор проверок готовности. Приложение не готово, пока хотя бы одна проверка
+// не проходит или после начала остановки
+type Checker struct {
+	timeout      time.Duration
+	dependencies []dependency
+	shuttingDown atomic.Bool
+}
+
+// NewChecker : timeout ограничивает время каждой проверки
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{timeout: timeout}
+}
+
+// Add : Добавление проверки зависимости name. Проверки добавляются до запуска веб сервера
+func (c *Checker) Add(name string, check Check) {
+	c.dependencies = append(c.dependencies, dependency{name: name, check: check})
+}
+
+// Shutdown : Перевод приложения в неготовое состояние перед остановкой веб сервера,
+// чтобы балансировщик перестал направлять запросы
+func (c *Checker) Shutdown() {
+	c.shuttingDown.Store(true)
+}
+
+// Check : Одновременный запуск всех проверок и результат по каждой зависимости.
+// Во время остановки проверки не запускаются
+func (c *Checker) Check(ctx context.Context) models.ReadinessResponse {
+	result := models.ReadinessResponse{
+		Status:       StatusReady,
+		Dependencies: make(map[string]models.DependencyStatus, len(c.dependencies)),
+	}
+	if c.shuttingDown.Load() {
+		result.Status = StatusShuttingDown
+		return result
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, dep := range c.dependencies {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			status := c.run(ctx, dep.check)
+
+			mu.Lock()
+			defer mu.Unlock()
+			result.Dependencies[dep.name] = status
+			if status.Status != StatusUp {
+				result.Status = StatusNotReady
+			}
+		}()
+	}
+	wg.Wait()
+
+	return result
+}
+
+// run : Запуск одной проверки с таймаутом
+func (c *Checker) run(ctx context.Context, check Check) models.DependencyStatus {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	status := models.DependencyStatus{
+		Status:     StatusUp,
+		DurationMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		status.Status = StatusDown
+		status.Error = err.Error()
+	}
+	return status
+}
@@ -0,0 +1,56 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error { return nil })
+
+	result := checker.Check(context.Background())
+	assert.Equal(t, StatusReady, result.Status)
+	require.Contains(t, result.Dependencies, "database")
+	assert.Equal(t, StatusUp, result.Dependencies["database"].Status)
+	assert.Empty(t, result.Dependencies["database"].Error)
+
+	checker.Add("migrations", func(ctx context.Context) error { return errors.New("database version 3, application version 4") })
+
+	result = checker.Check(context.Background())
+	assert.Equal(t, StatusNotReady, result.Status)
+	assert.Equal(t, StatusUp, result.Dependencies["database"].Status)
+	assert.Equal(t, StatusDown, result.Dependencies["migrations"].Status)
+	assert.Equal(t, "database version 3, application version 4", result.Dependencies["migrations"].Error)
+}
+
+func TestCheckTimeout(t *testing.T) {
+	checker := NewChecker(10 * time.Millisecond)
+	checker.Add("enrichment", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	result := checker.Check(context.Background())
+	assert.Equal(t, StatusNotReady, result.Status)
+	assert.Equal(t, StatusDown, result.Dependencies["enrichment"].Status)
+}
+
+func TestShutdown(t *testing.T) {
+	checked := false
+	checker := NewChecker(time.Second)
+	checker.Add("database", func(ctx context.Context) error {
+		checked = true
+		return nil
+	})
+
+	checker.Shutdown()
+	result := checker.Check(context.Background())
+	assert.Equal(t, StatusShuttingDown, result.Status)
+	assert.False(t, checked)
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"EffectiveMobile/internal/models"
+)
+
+// cacheCollector : счетчики кэша чтения песен, читаются при каждом сборе метрик
+type cacheCollector struct {
+	stats func() []models.CacheStats
+
+	hits      *prometheus.Desc
+	misses    *prometheus.Desc
+	evictions *prometheus.Desc
+	size      *prometheus.Desc
+}
+
+// RegisterCache : Регистрация метрик кэша чтения песен, stats - функция его счетчиков
+func RegisterCache(stats func() []models.CacheStats) {
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "cache", name), help, []string{"cache"}, nil)
+	}
+	Registry.MustRegister(&cacheCollector{
+		stats:     stats,
+		hits:      desc("hits_total", "Song read cache hits."),
+		misses:    desc("misses_total", "Song read cache misses."),
+		evictions: desc("evictions_total", "Entries evicted from the song read cache because it was full."),
+		size:      desc("entries", "Entries in the song read cache."),
+	})
+}
+
+func (c *cacheCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{c.hits, c.misses, c.evictions, c.size} {
+		ch <- desc
+	}
+}
+
+func (c *cacheCollector) Collect(ch chan<- prometheus.Metric) {
+	for _, stats := range c.stats() {
+		ch <- prometheus.MustNewConstMetric(c.hits, prometheus.CounterValue, float64(stats.Hits), stats.Name)
+		ch <- prometheus.MustNewConstMetric(c.misses, prometheus.CounterValue, float64(stats.Misses), stats.Name)
+		ch <- prometheus.MustNewConstMetric(c.evictions, prometheus.CounterValue, float64(stats.Evictions), stats.Name)
+		ch <- prometheus.MustNewConstMetric(c.size, prometheus.GaugeValue, float64(stats.Size), stats.Name)
+	}
+}
@@ -0,0 +1,102 @@
+// Package metrics собирает метрики Prometheus приложения: HTTP запросы, методы хранилища,
+// пул соединений с базой данных, кэш и обогащение песен
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "music_store"
+
+var (
+	// Registry : реестр метрик приложения, отдается обработчиком Handler
+	Registry = prometheus.NewRegistry()
+
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "HTTP requests by method, chi route pattern and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "HTTP request latency by method and chi route pattern.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	storageDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "storage",
+		Name:      "query_duration_seconds",
+		Help:      "Storage method latency including all its database queries.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5},
+	}, []string{"method"})
+
+	enrichmentRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "enrichment",
+		Name:      "requests_total",
+		Help:      "Music info API requests by outcome: success, bad_request, upstream_error or timeout.",
+	}, []string{"outcome"})
+
+	enrichmentDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "enrichment",
+		Name:      "request_duration_seconds",
+		Help:      "Music info API latency by outcome.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"outcome"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		storageDuration,
+		enrichmentRequests,
+		enrichmentDuration,
+	)
+}
+
+// Handler : Обработчик /metrics в формате Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// httpMethods : методы HTTP, которые учитываются под своим именем, остальные - как "other"
+var httpMethods = map[string]bool{
+	http.MethodGet: true, http.MethodHead: true, http.MethodPost: true, http.MethodPut: true, http.MethodPatch: true,
+	http.MethodDelete: true, http.MethodConnect: true, http.MethodOptions: true, http.MethodTrace: true,
+}
+
+// ObserveHTTP : Учет HTTP запроса. route - шаблон маршрута chi, а не путь запроса, а неизвестные методы
+// объединяются в "other", чтобы число рядов не зависело от ID в путях и от клиентов
+func ObserveHTTP(method string, route string, status int, duration time.Duration) {
+	if !httpMethods[method] {
+		method = "other"
+	}
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// ObserveStorage : Учет времени выполнения метода хранилища
+func ObserveStorage(method string, duration time.Duration) {
+	storageDuration.WithLabelValues(method).Observe(duration.Seconds())
+}
+
+// ObserveEnrichment : Учет запроса к внешнему API информации о песнях
+func ObserveEnrichment(outcome string, duration time.Duration) {
+	enrichmentRequests.WithLabelValues(outcome).Inc()
+	enrichmentDuration.WithLabelValues(outcome).Observe(duration.Seconds())
+}
@@ -0,0 +1,40 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestObserve(t *testing.T) {
+	ObserveHTTP(http.MethodGet, "/api/song/{id}", http.StatusOK, time.Millisecond)
+	ObserveHTTP(http.MethodGet, "/api/song/{id}", http.StatusOK, time.Millisecond)
+	ObserveHTTP("FOOBAR", "/api/song/{id}", http.StatusMethodNotAllowed, time.Millisecond)
+	ObserveStorage("ReadSong", time.Millisecond)
+	ObserveEnrichment("timeout", time.Second)
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(httpRequests.WithLabelValues(http.MethodGet, "/api/song/{id}", "200")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(httpRequests.WithLabelValues("other", "/api/song/{id}", "405")))
+	assert.Equal(t, 1, testutil.CollectAndCount(storageDuration, namespace+"_storage_query_duration_seconds"))
+	assert.Equal(t, 1.0, testutil.ToFloat64(enrichmentRequests.WithLabelValues("timeout")))
+}
+
+func TestRegisterCache(t *testing.T) {
+	RegisterCache(func() []models.CacheStats {
+		return []models.CacheStats{{Name: "songs", Hits: 3, Misses: 1, Size: 1}}
+	})
+
+	recorder := httptest.NewRecorder()
+	Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), `music_store_cache_hits_total{cache="songs"} 3`)
+	assert.Contains(t, recorder.Body.String(), `music_store_cache_entries{cache="songs"} 1`)
+	assert.Contains(t, recorder.Body.String(), "go_goroutines")
+}
@@ -0,0 +1,59 @@
+package metrics
+
+import (
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// poolCollector : статистика пула соединений pgxpool, читается при каждом сборе метрик
+type poolCollector struct {
+	pool *pgxpool.Pool
+
+	acquired     *prometheus.Desc
+	idle         *prometheus.Desc
+	constructing *prometheus.Desc
+	total        *prometheus.Desc
+	max          *prometheus.Desc
+	acquires     *prometheus.Desc
+	emptyAcquire *prometheus.Desc
+	canceled     *prometheus.Desc
+	waitSeconds  *prometheus.Desc
+}
+
+// RegisterPool : Регистрация метрик пула соединений с базой данных
+func RegisterPool(pool *pgxpool.Pool) {
+	desc := func(name string, help string) *prometheus.Desc {
+		return prometheus.NewDesc(prometheus.BuildFQName(namespace, "db_pool", name), help, nil, nil)
+	}
+	Registry.MustRegister(&poolCollector{
+		pool:         pool,
+		acquired:     desc("acquired_connections", "Connections currently acquired from the pool."),
+		idle:         desc("idle_connections", "Idle connections in the pool."),
+		constructing: desc("constructing_connections", "Connections being established."),
+		total:        desc("connections", "All connections in the pool."),
+		max:          desc("max_connections", "Maximum size of the pool."),
+		acquires:     desc("acquires_total", "Successful acquires from the pool."),
+		emptyAcquire: desc("empty_acquires_total", "Acquires that had to wait for a connection because the pool was empty."),
+		canceled:     desc("canceled_acquires_total", "Acquires canceled by their context."),
+		waitSeconds:  desc("acquire_duration_seconds_total", "Total time spent acquiring connections, including waiting for a free one."),
+	})
+}
+
+func (c *poolCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{c.acquired, c.idle, c.constructing, c.total, c.max, c.acquires, c.emptyAcquire, c.canceled, c.waitSeconds} {
+		ch <- desc
+	}
+}
+
+func (c *poolCollector) Collect(ch chan<- prometheus.Metric) {
+	stat := c.pool.Stat()
+	ch <- prometheus.MustNewConstMetric(c.acquired, prometheus.GaugeValue, float64(stat.AcquiredConns()))
+	ch <- prometheus.MustNewConstMetric(c.idle, prometheus.GaugeValue, float64(stat.IdleConns()))
+	ch <- prometheus.MustNewConstMetric(c.constructing, prometheus.GaugeValue, float64(stat.ConstructingConns()))
+	ch <- prometheus.MustNewConstMetric(c.total, prometheus.GaugeValue, float64(stat.TotalConns()))
+	ch <- prometheus.MustNewConstMetric(c.max, prometheus.GaugeValue, float64(stat.MaxConns()))
+	ch <- prometheus.MustNewConstMetric(c.acquires, prometheus.CounterValue, float64(stat.AcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.emptyAcquire, prometheus.CounterValue, float64(stat.EmptyAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.canceled, prometheus.CounterValue, float64(stat.CanceledAcquireCount()))
+	ch <- prometheus.MustNewConstMetric(c.waitSeconds, prometheus.CounterValue, stat.AcquireDuration().Seconds())
+}
@@ -0,0 +1,351 @@
+// Package migrate применяет версионированные SQL миграции и хранит их состояние в таблице schema_migrations
+package migrate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// lockID : ключ advisory lock, не дающий нескольким экземплярам применять миграции одновременно
+const lockID int64 = 7243912001
+
+var (
+	// ErrDatabaseAhead : в базе применены миграции, неизвестные этой версии приложения
+	ErrDatabaseAhead = errors.New("database schema is newer than the application")
+	// ErrDatabaseBehind : в базе применены не все миграции этой версии приложения
+	ErrDatabaseBehind = errors.New("database schema is older than the application")
+
+	fileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+)
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+}
+
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+	loger      *zap.SugaredLogger
+}
+
+// NewMigrator : Загрузка миграций из каталога dir файловой системы fsys
+func NewMigrator(db *pgxpool.Pool, fsys fs.FS, dir string, loger *zap.SugaredLogger) (*Migrator, error) {
+	migrations, err := Load(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+		loger:      loger,
+	}, nil
+}
+
+// Load : Чтение миграций из каталога, у каждой версии должен быть up файл
+func Load(fsys fs.FS, dir string) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %v: %w", entry.Name(), err)
+		}
+
+		data, err := fs.ReadFile(fsys, path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration %v: version is used by %v", entry.Name(), migration.Name)
+		}
+
+		if match[3] == "up" {
+			migration.Up = string(data)
+		} else {
+			migration.Down = string(data)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" {
+			return nil, fmt.Errorf("migration %v_%v: up file is missing", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, *migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// Latest : Последняя версия схемы, известная приложению
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Version : Текущая версия схемы базы данных
+func (m *Migrator) Version(ctx context.Context) (int64, error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer conn.Release()
+
+	applied, err := m.applied(ctx, conn.Conn())
+	if err != nil {
+		return 0, err
+	}
+	return maxVersion(applied), nil
+}
+
+// Check : Проверка, что база данных не новее приложения
+func (m *Migrator) Check(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	if version > m.Latest() {
+		return fmt.Errorf("%w: database version %v, application version %v", ErrDatabaseAhead, version, m.Latest())
+	}
+	return nil
+}
+
+// Current : Проверка, что версия схемы базы данных совпадает с версией приложения
+func (m *Migrator) Current(ctx context.Context) error {
+	version, err := m.Version(ctx)
+	if err != nil {
+		return err
+	}
+	switch {
+	case version > m.Latest():
+		return fmt.Errorf("%w: database version %v, application version %v", ErrDatabaseAhead, version, m.Latest())
+	case version < m.Latest():
+		return fmt.Errorf("%w: database version %v, application version %v", ErrDatabaseBehind, version, m.Latest())
+	}
+	return nil
+}
+
+// Up : Применение всех неприменённых миграций, возвращает их количество
+func (m *Migrator) Up(ctx context.Context) (count int, err error) {
+	err = m.withLock(ctx, func(conn *pgx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if version := maxVersion(applied); version > m.Latest() {
+			return fmt.Errorf("%w: database version %v, application version %v", ErrDatabaseAhead, version, m.Latest())
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			m.loger.Infof("Applying migration %v_%v", migration.Version, migration.Name)
+			err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Up); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "INSERT INTO public.schema_migrations (version, name) VALUES ($1, $2)", migration.Version, migration.Name)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %v_%v: %w", migration.Version, migration.Name, err)
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Down : Откат последних steps применённых миграций
+func (m *Migrator) Down(ctx context.Context, steps int) (count int, err error) {
+	known := make(map[int64]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	err = m.withLock(ctx, func(conn *pgx.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		versions := make([]int64, 0, len(applied))
+		for version := range applied {
+			versions = append(versions, version)
+		}
+		sort.Slice(versions, func(i, j int) bool {
+			return versions[i] > versions[j]
+		})
+
+		for _, version := range versions {
+			if count == steps {
+				break
+			}
+
+			migration, ok := known[version]
+			if !ok {
+				return fmt.Errorf("%w: migration %v is unknown", ErrDatabaseAhead, version)
+			}
+			if migration.Down == "" {
+				return fmt.Errorf("migration %v_%v: down file is missing", migration.Version, migration.Name)
+			}
+
+			m.loger.Infof("Reverting migration %v_%v", migration.Version, migration.Name)
+			err = pgx.BeginFunc(ctx, conn, func(tx pgx.Tx) error {
+				if _, err := tx.Exec(ctx, migration.Down); err != nil {
+					return err
+				}
+				_, err := tx.Exec(ctx, "DELETE FROM public.schema_migrations WHERE version = $1", migration.Version)
+				return err
+			})
+			if err != nil {
+				return fmt.Errorf("migration %v_%v: %w", migration.Version, migration.Name, err)
+			}
+			count++
+		}
+		return nil
+	})
+	return count, err
+}
+
+// Status : Состояние всех известных и применённых миграций
+func (m *Migrator) Status(ctx context.Context) (result []Status, err error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	applied, err := m.applied(ctx, conn.Conn())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if row, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = row.AppliedAt
+			delete(applied, migration.Version)
+		}
+		result = append(result, status)
+	}
+	// Миграции из более новой версии приложения
+	for _, row := range applied {
+		result = append(result, row)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// withLock : Выполнение fn на выделенном соединении под advisory lock
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *pgx.Conn) error) error {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Release()
+
+	if _, err = conn.Exec(ctx, "SELECT pg_advisory_lock($1)", lockID); err != nil {
+		return err
+	}
+	defer func() {
+		if _, err := conn.Exec(context.Background(), "SELECT pg_advisory_unlock($1)", lockID); err != nil {
+			m.loger.Errorf("Error releasing migration lock: %v", err)
+		}
+	}()
+
+	_, err = conn.Exec(ctx, `CREATE TABLE IF NOT EXISTS public.schema_migrations (
+		version bigint primary key,
+		name varchar(255) not null,
+		applied_at timestamp not null default (now() at time zone 'utc')
+	)`)
+	if err != nil {
+		return err
+	}
+
+	return fn(conn.Conn())
+}
+
+// applied : Применённые миграции, пустой результат если таблицы schema_migrations еще нет
+func (m *Migrator) applied(ctx context.Context, conn *pgx.Conn) (map[int64]Status, error) {
+	result := make(map[int64]Status)
+
+	var exists bool
+	err := conn.QueryRow(ctx, "SELECT to_regclass('public.schema_migrations') IS NOT NULL").Scan(&exists)
+	if err != nil || !exists {
+		return result, err
+	}
+
+	rows, err := conn.Query(ctx, "SELECT version, name, applied_at FROM public.schema_migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	var status Status
+	_, err = pgx.ForEachRow(rows, []any{&status.Version, &status.Name, &status.AppliedAt}, func() error {
+		status.Applied = true
+		result[status.Version] = status
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func maxVersion(applied map[int64]Status) (version int64) {
+	for v := range applied {
+		if v > version {
+			version = v
+		}
+	}
+	return version
+}
@@ -0,0 +1,57 @@
+package migrate
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/schema"
+)
+
+func TestLoad(t *testing.T) {
+	fsys := fstest.MapFS{
+		"postgres/000002_search.up.sql":   {Data: []byte("up 2")},
+		"postgres/000002_search.down.sql": {Data: []byte("down 2")},
+		"postgres/000001_init.up.sql":     {Data: []byte("up 1")},
+		"postgres/README.md":              {Data: []byte("not a migration")},
+	}
+
+	migrations, err := Load(fsys, "postgres")
+	require.NoError(t, err)
+	assert.Equal(t, []Migration{
+		{Version: 1, Name: "init", Up: "up 1"},
+		{Version: 2, Name: "search", Up: "up 2", Down: "down 2"},
+	}, migrations)
+}
+
+func TestLoadMissingUp(t *testing.T) {
+	fsys := fstest.MapFS{
+		"postgres/000001_init.down.sql": {Data: []byte("down 1")},
+	}
+
+	_, err := Load(fsys, "postgres")
+	assert.Error(t, err)
+}
+
+func TestLoadDuplicateVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"postgres/000001_init.up.sql":  {Data: []byte("up 1")},
+		"postgres/000001_other.up.sql": {Data: []byte("up 1")},
+	}
+
+	_, err := Load(fsys, "postgres")
+	assert.Error(t, err)
+}
+
+func TestLoadEmbeddedSchema(t *testing.T) {
+	migrations, err := Load(schema.Postgres, "postgres")
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, migration := range migrations {
+		assert.Equal(t, int64(i+1), migration.Version, "migrations must be numbered without gaps")
+		assert.NotEmpty(t, migration.Down, "migration %v_%v has no down file", migration.Version, migration.Name)
+	}
+}
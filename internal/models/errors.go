@@ -0,0 +1,77 @@
+package models
+
+import "errors"
+
+// Виды доменных ошибок, по ним обработчики выбирают HTTP статус ответа
+var (
+	ErrBadRequest         = errors.New("bad request")
+	ErrValidation         = errors.New("validation failed")
+	ErrNotFound           = errors.New("not found")
+	ErrConflict           = errors.New("conflict")
+	ErrUpstream           = errors.New("upstream failure")
+	ErrPreconditionFailed = errors.New("precondition failed")
+	ErrUnauthorized       = errors.New("unauthorized")
+	ErrForbidden          = errors.New("forbidden")
+	ErrTooManyRequests    = errors.New("too many requests")
+)
+
+// Стабильные машиночитаемые коды ошибок для ErrorResponse
+const (
+	CodeInternal          = "internal_error"
+	CodeInvalidBody       = "invalid_body"
+	CodeInvalidParameter  = "invalid_parameter"
+	CodeInvalidID         = "invalid_id"
+	CodeInvalidValue      = "invalid_value"
+	CodeInvalidFilter     = "invalid_filter"
+	CodeInvalidSort       = "invalid_sort"
+	CodeInvalidPagination = "invalid_pagination"
+	CodeInvalidPageToken  = "invalid_page_token"
+	CodeInvalidCouplet    = "invalid_couplet_id"
+	CodeInvalidRevision   = "invalid_revision"
+	CodeUnsupportedFormat = "unsupported_format"
+	CodeSongNotFound      = "song_not_found"
+	CodeSongExists        = "song_already_exists"
+	CodeRevisionNotFound  = "revision_not_found"
+	CodeCoupletNotFound   = "couplet_not_found"
+	CodeArtistNotFound    = "artist_not_found"
+	CodeArtistExists      = "artist_already_exists"
+	CodeArtistInUse       = "artist_in_use"
+	CodeInvalidPosition   = "invalid_position"
+	CodeUnauthorized      = "unauthorized"
+	CodeForbidden         = "forbidden"
+	CodeRateLimited       = "rate_limited"
+	CodeMusicInfoFailed   = "music_info_unavailable"
+	CodeVersionMismatch   = "version_mismatch"
+)
+
+// DomainError : ошибка бизнес-логики с видом, кодом и сообщением для клиента.
+// Исходная ошибка Err попадает только в логи
+type DomainError struct {
+	Kind    error
+	Code    string
+	Message string
+	Err     error
+}
+
+func NewError(kind error, code string, message string, err error) *DomainError {
+	return &DomainError{
+		Kind:    kind,
+		Code:    code,
+		Message: message,
+		Err:     err,
+	}
+}
+
+func (e *DomainError) Error() string {
+	if e.Err != nil {
+		return e.Message + ": " + e.Err.Error()
+	}
+	return e.Message
+}
+
+func (e *DomainError) Unwrap() []error {
+	if e.Err != nil {
+		return []error{e.Kind, e.Err}
+	}
+	return []error{e.Kind}
+}
@@ -1,6 +1,9 @@
 package models
 
-import "database/sql"
+import (
+	"database/sql"
+	"time"
+)
 
 type Song struct {
 	ID      string `json:"id"`
@@ -9,6 +12,10 @@ type Song struct {
 	Release string `json:"releaseDate,omitempty"`
 	Text    string `json:"text,omitempty"`
 	Link    string `json:"link,omitempty"`
+	// Version : номер версии песни, передается клиенту в заголовке ETag
+	Version int `json:"-"`
+	// Lyrics : разобранный на части текст песни, сохраняется вместе с ним
+	Lyrics []LyricsSection `json:"-"`
 }
 
 type SongDB struct {
@@ -18,6 +25,7 @@ type SongDB struct {
 	Release sql.NullTime   `db:"release_date"`
 	Text    sql.NullString `db:"song_text"`
 	Link    sql.NullString `db:"link"`
+	Version int            `db:"version"`
 }
 
 type SongRequest struct {
@@ -25,6 +33,23 @@ type SongRequest struct {
 	Artist string `json:"group"`
 }
 
+// PatchField : поле JSON Merge Patch. Set - поле передано в патче, Null - передано явное null
+type PatchField struct {
+	Set   bool
+	Null  bool
+	Value string
+}
+
+type SongPatch struct {
+	Name    PatchField
+	Artist  PatchField
+	Release PatchField
+	Text    PatchField
+	Link    PatchField
+	// Lyrics : разобранный текст, записывается вместе с Text
+	Lyrics []LyricsSection
+}
+
 type SongResponse struct {
 	ID string `json:"id"`
 }
@@ -35,6 +60,12 @@ type SongInfoResponse struct {
 	Link    string `json:"link"`
 }
 
+type SongDetail struct {
+	Release string `json:"releaseDate"`
+	Text    string `json:"text"`
+	Link    string `json:"link"`
+}
+
 type SongInfoResponseDB struct {
 	Release sql.NullTime
 	Text    sql.NullString
@@ -42,21 +73,319 @@ type SongInfoResponseDB struct {
 }
 
 type SongsListResponse struct {
-	Songs []Song `json:"songs"`
+	Songs         []Song `json:"songs"`
+	NextPageToken string `json:"nextPageToken,omitempty"`
 }
 
 type SongsListResponseDB struct {
 	Songs []SongDB `json:"songs"`
 }
 
+type SongSearchResult struct {
+	Song
+	Rank    float32 `json:"rank"`
+	Snippet string  `json:"snippet"`
+}
+
+type SongSearchResultDB struct {
+	SongDB
+	Rank    float32 `db:"rank"`
+	Snippet string  `db:"snippet"`
+}
+
+type SongsSearchResponse struct {
+	Results []SongSearchResult `json:"results"`
+}
+
+// SongRevision : снимок песни после изменения. Для удаления это последнее состояние песни перед ним
+type SongRevision struct {
+	Revision  int       `json:"revision"`
+	Operation string    `json:"operation"`
+	Song      Song      `json:"song"`
+	RequestID string    `json:"requestId,omitempty"`
+	Actor     string    `json:"actor,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type SongRevisionDB struct {
+	SongDB
+	Revision  int            `db:"revision"`
+	Operation string         `db:"operation"`
+	RequestID sql.NullString `db:"request_id"`
+	Actor     sql.NullString `db:"actor"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+type SongRevisionsResponse struct {
+	Revisions []SongRevision `json:"revisions"`
+}
+
+// FieldChange : изменение одного поля песни между двумя ревизиями
+type FieldChange struct {
+	Field string `json:"field"`
+	From  string `json:"from"`
+	To    string `json:"to"`
+}
+
+type SongRevisionDiff struct {
+	ID      string        `json:"id"`
+	From    int           `json:"from"`
+	To      int           `json:"to"`
+	Changes []FieldChange `json:"changes"`
+}
+
+// Artist : исполнитель, SongsCount - число его неудаленных песен
+type Artist struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	SongsCount int    `json:"songsCount"`
+}
+
+type ArtistDB struct {
+	ID         string `db:"id"`
+	Name       string `db:"name"`
+	SongsCount int    `db:"songs_count"`
+}
+
+type ArtistRequest struct {
+	Name string `json:"name"`
+}
+
+type ArtistResponse struct {
+	ID string `json:"id"`
+}
+
+type ArtistsListResponse struct {
+	Artists []Artist `json:"artists"`
+}
+
+// Album : альбом исполнителя с треками в порядке позиций, позиции нумеруются с 1.
+// В списке альбомов треки не возвращаются
+type Album struct {
+	ID        string       `json:"id"`
+	Title     string       `json:"title"`
+	ArtistID  string       `json:"artistId"`
+	Artist    string       `json:"artist"`
+	Release   string       `json:"releaseDate"`
+	CoverLink string       `json:"coverLink"`
+	Tracks    []AlbumTrack `json:"tracks,omitempty"`
+}
+
+type AlbumDB struct {
+	ID        string         `db:"id"`
+	Title     string         `db:"title"`
+	ArtistID  string         `db:"artist_id"`
+	Artist    string         `db:"artist_name"`
+	Release   sql.NullTime   `db:"release_date"`
+	CoverLink sql.NullString `db:"cover_link"`
+}
+
+type AlbumTrack struct {
+	Position int  `json:"position"`
+	Song     Song `json:"song"`
+}
+
+type AlbumTrackDB struct {
+	Position int `db:"position"`
+	SongDB
+}
+
+// AlbumRequest : Tracks - ID песен в порядке треков
+type AlbumRequest struct {
+	Title     string   `json:"title"`
+	ArtistID  string   `json:"artistId"`
+	Release   string   `json:"releaseDate"`
+	CoverLink string   `json:"coverLink"`
+	Tracks    []string `json:"tracks"`
+}
+
+type AlbumTracksRequest struct {
+	Tracks []string `json:"tracks"`
+}
+
+type AlbumTrackRequest struct {
+	SongID string `json:"songId"`
+}
+
+type AlbumResponse struct {
+	ID string `json:"id"`
+}
+
+type AlbumsListResponse struct {
+	Albums []Album `json:"albums"`
+}
+
+// Playlist : плейлист с записями в порядке позиций, позиции нумеруются с 1 и идут подряд.
+// Записи песен из корзины сохраняют позицию, но не возвращаются. В списке плейлистов записи не возвращаются
+type Playlist struct {
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Owner       string          `json:"owner,omitempty"`
+	Entries     []PlaylistEntry `json:"entries,omitempty"`
+}
+
+type PlaylistDB struct {
+	ID          string         `db:"id"`
+	Name        string         `db:"name"`
+	Description sql.NullString `db:"description"`
+	Owner       sql.NullString `db:"owner"`
+}
+
+type PlaylistEntry struct {
+	Position int  `json:"position"`
+	Song     Song `json:"song"`
+}
+
+type PlaylistEntryDB struct {
+	Position int `db:"position"`
+	SongDB
+}
+
+type PlaylistRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// PlaylistEntryRequest : Position - позиция новой записи, 0 - в конец плейлиста
+type PlaylistEntryRequest struct {
+	SongID   string `json:"songId"`
+	Position int    `json:"position"`
+}
+
+type PlaylistMoveRequest struct {
+	Position int `json:"position"`
+}
+
+type PlaylistResponse struct {
+	ID string `json:"id"`
+}
+
+type PlaylistsListResponse struct {
+	Playlists []Playlist `json:"playlists"`
+}
+
+// APIKey : ключ API сервисного клиента. Сам ключ не хранится, Prefix - его начало для опознания в списке.
+// Scopes - роли ключа, ExpiresAt равный nil - бессрочный ключ
+type APIKey struct {
+	ID         string     `json:"id"`
+	Name       string     `json:"name"`
+	Prefix     string     `json:"prefix"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  *time.Time `json:"expiresAt,omitempty"`
+	CreatedBy  string     `json:"createdBy,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt,omitempty"`
+	RevokedAt  *time.Time `json:"revokedAt,omitempty"`
+}
+
+type APIKeyDB struct {
+	ID         string         `db:"id"`
+	Name       string         `db:"name"`
+	Prefix     string         `db:"prefix"`
+	Scopes     []string       `db:"scopes"`
+	ExpiresAt  sql.NullTime   `db:"expires_at"`
+	CreatedBy  sql.NullString `db:"created_by"`
+	CreatedAt  time.Time      `db:"created_at"`
+	LastUsedAt sql.NullTime   `db:"last_used_at"`
+	RevokedAt  sql.NullTime   `db:"revoked_at"`
+}
+
+type APIKeyRequest struct {
+	Name      string     `json:"name"`
+	Scopes    []string   `json:"scopes"`
+	ExpiresAt *time.Time `json:"expiresAt"`
+}
+
+// APIKeyIssued : выданный ключ. Key возвращается только при выпуске и ротации
+type APIKeyIssued struct {
+	APIKey
+	Key string `json:"key"`
+}
+
+type APIKeyResponse struct {
+	ID string `json:"id"`
+}
+
+type APIKeysListResponse struct {
+	Keys []APIKey `json:"keys"`
+}
+
+// TrashedSong : песня в корзине и время ее удаления
+type TrashedSong struct {
+	Song
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+type TrashedSongDB struct {
+	SongDB
+	DeletedAt time.Time `db:"deleted_at"`
+}
+
+type TrashResponse struct {
+	Songs []TrashedSong `json:"songs"`
+}
+
+// ImportRow : строка файла импорта с номером, начиная с 1. Error - причина, по которой строка не прошла проверку
+type ImportRow struct {
+	Row   int
+	Song  Song
+	Error string
+}
+
+// Статусы строк в отчете об импорте
+const (
+	ImportCreated   = "created"
+	ImportDuplicate = "duplicate"
+	ImportInvalid   = "invalid"
+)
+
+type ImportRowResult struct {
+	Row    int    `json:"row"`
+	Status string `json:"status"`
+	ID     string `json:"id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+type ImportReport struct {
+	DryRun    bool              `json:"dryRun"`
+	Created   int               `json:"created"`
+	Duplicate int               `json:"duplicate"`
+	Invalid   int               `json:"invalid"`
+	Rows      []ImportRowResult `json:"rows"`
+}
+
+// LyricsSection : часть текста песни - куплет, припев, бридж и т.п.
+// Index - номер части среди частей того же типа, начиная с 1
+type LyricsSection struct {
+	Type  string   `json:"type"`
+	Index int      `json:"index"`
+	Lines []string `json:"lines"`
+}
+
+type SongLyricsResponse struct {
+	ID       string          `json:"id"`
+	Sections []LyricsSection `json:"sections"`
+}
+
+// SongVerseResponse : CoupletId - номер первого запрошенного куплета, Couplet - текст запрошенных куплетов,
+// разделенных пустой строкой. Куплеты нумеруются с 1
 type SongVerseResponse struct {
-	ID        string `json:"id"`
-	CoupletId int    `json:"coupletId"`
-	Couplet   string `json:"couplet"`
+	ID            string        `json:"id"`
+	CoupletId     int           `json:"coupletId"`
+	Couplet       string        `json:"couplet"`
+	Couplets      []SongCouplet `json:"couplets"`
+	TotalCouplets int           `json:"totalCouplets"`
+}
+
+type SongCouplet struct {
+	Number int    `json:"number"`
+	Text   string `json:"text"`
 }
 
 type ErrorResponse struct {
 	RequestID string `json:"requestId"`
+	Code      string `json:"code"`
 	Error     string `json:"error"`
 }
 
@@ -69,4 +398,41 @@ type PaginationOptions struct {
 	Limit     string
 	Offset    string
 	PageToken string
+	Cursor    *PageCursor
+}
+
+// PageCursor : позиция keyset-пагинации - поле и направление сортировки и последняя выданная пара (значение, id)
+type PageCursor struct {
+	Field string
+	Order string
+	Value *string
+	ID    string
+}
+
+// CacheStats : счетчики кэша чтения песен
+type CacheStats struct {
+	Name      string  `json:"name"`
+	Hits      uint64  `json:"hits"`
+	Misses    uint64  `json:"misses"`
+	Evictions uint64  `json:"evictions"`
+	Size      int     `json:"size"`
+	Capacity  int     `json:"capacity"`
+	HitRatio  float64 `json:"hitRatio"`
+}
+
+type CacheStatsResponse struct {
+	Enabled bool         `json:"enabled"`
+	Caches  []CacheStats `json:"caches"`
+}
+
+// DependencyStatus : результат проверки зависимости приложения для /readyz
+type DependencyStatus struct {
+	Status     string  `json:"status"`
+	DurationMs float64 `json:"durationMs"`
+	Error      string  `json:"error,omitempty"`
+}
+
+type ReadinessResponse struct {
+	Status       string                      `json:"status"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
 }
@@ -0,0 +1,84 @@
+// Package pagetoken кодирует курсор keyset-пагинации в непрозрачный подписанный токен
+package pagetoken
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+
+	"EffectiveMobile/internal/models"
+)
+
+var ErrInvalidToken = errors.New("page token is not valid")
+
+type Signer struct {
+	secret []byte
+}
+
+type payload struct {
+	Field string  `json:"f"`
+	Order string  `json:"o"`
+	Value *string `json:"v"`
+	ID    string  `json:"id"`
+}
+
+func NewSigner(secret []byte) *Signer {
+	return &Signer{
+		secret: secret,
+	}
+}
+
+// Encode : Кодирование курсора в токен вида base64(payload).base64(hmac)
+func (s *Signer) Encode(cursor models.PageCursor) (string, error) {
+	data, err := json.Marshal(payload{
+		Field: cursor.Field,
+		Order: cursor.Order,
+		Value: cursor.Value,
+		ID:    cursor.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	body := base64.RawURLEncoding.EncodeToString(data)
+	return body + "." + base64.RawURLEncoding.EncodeToString(s.sign(body)), nil
+}
+
+// Decode : Проверка подписи токена и декодирование курсора
+func (s *Signer) Decode(token string) (cursor models.PageCursor, err error) {
+	body, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return cursor, ErrInvalidToken
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, s.sign(body)) {
+		return cursor, ErrInvalidToken
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(body)
+	if err != nil {
+		return cursor, ErrInvalidToken
+	}
+
+	var p payload
+	if err = json.Unmarshal(data, &p); err != nil || p.Field == "" || p.ID == "" {
+		return cursor, ErrInvalidToken
+	}
+
+	return models.PageCursor{
+		Field: p.Field,
+		Order: p.Order,
+		Value: p.Value,
+		ID:    p.ID,
+	}, nil
+}
+
+func (s *Signer) sign(body string) []byte {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(body))
+	return mac.Sum(nil)
+}
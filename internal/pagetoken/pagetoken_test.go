@@ -0,0 +1,60 @@
+package pagetoken
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	value := "Supermassive Black Hole"
+	cursor := models.PageCursor{
+		Field: "song",
+		Order: "asc",
+		Value: &value,
+		ID:    "7b0c2a8e-4f0d-4d4e-9b8a-3c1f2d5e6a7b",
+	}
+
+	token, err := signer.Encode(cursor)
+	require.NoError(t, err)
+
+	decoded, err := signer.Decode(token)
+	require.NoError(t, err)
+	assert.Equal(t, cursor, decoded)
+}
+
+func TestDecodeNullValue(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+	cursor := models.PageCursor{
+		Field: "release",
+		Order: "desc",
+		ID:    "7b0c2a8e-4f0d-4d4e-9b8a-3c1f2d5e6a7b",
+	}
+
+	token, err := signer.Encode(cursor)
+	require.NoError(t, err)
+
+	decoded, err := signer.Decode(token)
+	require.NoError(t, err)
+	assert.Nil(t, decoded.Value)
+}
+
+func TestDecodeTamperedToken(t *testing.T) {
+	signer := NewSigner([]byte("secret"))
+
+	token, err := signer.Encode(models.PageCursor{Field: "id", Order: "desc", ID: "1"})
+	require.NoError(t, err)
+
+	_, err = NewSigner([]byte("other")).Decode(token)
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = signer.Decode("0")
+	assert.ErrorIs(t, err, ErrInvalidToken)
+
+	_, err = signer.Decode("e30." + token[len(token)-10:])
+	assert.ErrorIs(t, err, ErrInvalidToken)
+}
@@ -0,0 +1,77 @@
+package ratelimit
+
+import (
+	"context"
+	"math"
+	"sync"
+	"time"
+)
+
+// sweepEvery : через сколько списаний MemoryStore удаляет заполнившиеся корзины
+const sweepEvery = 1024
+
+type bucket struct {
+	tokens  float64
+	updated time.Time
+	period  time.Duration
+}
+
+// MemoryStore : хранилище корзин в памяти процесса. Лимиты считаются отдельно в каждой реплике
+type MemoryStore struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	takes   int
+	now     func() time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: map[string]*bucket{},
+		now:     time.Now,
+	}
+}
+
+// Take : Списание запроса из корзины key. Новая корзина создается полной
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	capacity := float64(limit.Requests)
+	// Скорость пополнения корзины в запросах за наносекунду
+	rate := capacity / float64(limit.Period)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: capacity, updated: now}
+		s.buckets[key] = b
+	}
+	b.tokens = math.Min(capacity, b.tokens+float64(now.Sub(b.updated))*rate)
+	b.updated = now
+	b.period = limit.Period
+
+	result := Result{Limit: limit.Requests}
+	if b.tokens >= 1 {
+		b.tokens--
+		result.Allowed = true
+	} else {
+		result.RetryAfter = time.Duration(math.Ceil((1 - b.tokens) / rate))
+	}
+	result.Remaining = int(b.tokens)
+	result.Reset = time.Duration(math.Ceil((capacity - b.tokens) / rate))
+
+	s.takes++
+	if s.takes%sweepEvery == 0 {
+		s.sweep(now)
+	}
+	return result, nil
+}
+
+// sweep : Удаление корзин, которые успели заполниться: они не отличаются от новых
+func (s *MemoryStore) sweep(now time.Time) {
+	for key, b := range s.buckets {
+		if now.Sub(b.updated) >= b.period {
+			delete(s.buckets, key)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+// Package ratelimit ограничивает частоту запросов клиентов API алгоритмом token bucket.
+// Состояние корзин хранится в Store: в памяти процесса или в общем хранилище для нескольких реплик
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Group : группа маршрутов с общим лимитом
+type Group string
+
+const (
+	GroupReads  Group = "reads"
+	GroupWrites Group = "writes"
+	GroupBulk   Group = "bulk"
+	// GroupAuth : проверки ключей API по IP адресу клиента до обращения к базе данных
+	GroupAuth Group = "auth"
+)
+
+// Limit : не больше Requests запросов за Period. Корзина вмещает Requests запросов
+// и равномерно пополняется за Period. Нулевой лимит не ограничивает запросы
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit : Разбор лимита вида "100/1m", пустая строка или "0" - без ограничения
+func ParseLimit(value string) (Limit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" {
+		return Limit{}, nil
+	}
+
+	requests, period, ok := strings.Cut(value, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("rate limit %q must look like 100/1m", value)
+	}
+	limit := Limit{}
+	var err error
+	if limit.Requests, err = strconv.Atoi(requests); err != nil || limit.Requests < 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: requests must be a non-negative number", value)
+	}
+	if limit.Period, err = time.ParseDuration(period); err != nil || limit.Period <= 0 {
+		return Limit{}, fmt.Errorf("rate limit %q: period must be a positive duration", value)
+	}
+	return limit, nil
+}
+
+// Unlimited : Лимит не ограничивает запросы
+func (l Limit) Unlimited() bool {
+	return l.Requests == 0
+}
+
+func (l Limit) String() string {
+	if l.Unlimited() {
+		return "unlimited"
+	}
+	return fmt.Sprintf("%v/%v", l.Requests, l.Period)
+}
+
+// Result : состояние корзины клиента после запроса
+type Result struct {
+	Allowed bool
+	// Limit : вместимость корзины, 0 для группы без ограничения
+	Limit int
+	// Remaining : сколько запросов можно сделать сразу
+	Remaining int
+	// Reset : через сколько корзина заполнится полностью
+	Reset time.Duration
+	// RetryAfter : через сколько будет доступен следующий запрос, если этот отклонен
+	RetryAfter time.Duration
+}
+
+// Store : хранилище корзин клиентов. Take атомарно списывает запрос из корзины key с лимитом limit.
+// Реализация для общего хранилища (например Redis) должна выполнять списание одной операцией
+type Store interface {
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// Config : лимиты групп маршрутов и проверок ключей API
+type Config struct {
+	Reads  Limit
+	Writes Limit
+	Bulk   Limit
+	Auth   Limit
+}
+
+// Enabled : Ограничен ли хотя бы один вид запросов
+func (c Config) Enabled() bool {
+	return !c.Reads.Unlimited() || !c.Writes.Unlimited() || !c.Bulk.Unlimited() || !c.Auth.Unlimited()
+}
+
+// Limiter : лимиты групп маршрутов поверх хранилища корзин
+type Limiter struct {
+	limits map[Group]Limit
+	store  Store
+}
+
+func NewLimiter(config Config, store Store) *Limiter {
+	return &Limiter{
+		limits: map[Group]Limit{
+			GroupReads:  config.Reads,
+			GroupWrites: config.Writes,
+			GroupBulk:   config.Bulk,
+			GroupAuth:   config.Auth,
+		},
+		store: store,
+	}
+}
+
+// Limit : Лимит группы маршрутов
+func (l *Limiter) Limit(group Group) Limit {
+	return l.limits[group]
+}
+
+// Take : Списание запроса клиента client из корзины группы group.
+// У каждой группы своя корзина, массовые операции не расходуют лимит чтения
+func (l *Limiter) Take(ctx context.Context, group Group, client string) (Result, error) {
+	limit := l.limits[group]
+	if limit.Unlimited() {
+		return Result{Allowed: true}, nil
+	}
+	return l.store.Take(ctx, string(group)+":"+client, limit)
+}
@@ -0,0 +1,89 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLimit(t *testing.T) {
+	limit, err := ParseLimit("100/1m")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Requests: 100, Period: time.Minute}, limit)
+
+	for _, value := range []string{"", "0", " "} {
+		limit, err = ParseLimit(value)
+		require.NoError(t, err, value)
+		assert.True(t, limit.Unlimited(), value)
+	}
+
+	for _, value := range []string{"100", "x/1m", "-1/1m", "10/x", "10/0s"} {
+		_, err = ParseLimit(value)
+		assert.Error(t, err, value)
+	}
+}
+
+func TestMemoryStore(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore()
+	store.now = func() time.Time { return now }
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	for i := 2; i >= 0; i-- {
+		result, err := store.Take(context.Background(), "alice", limit)
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Equal(t, 3, result.Limit)
+		assert.Equal(t, i, result.Remaining)
+	}
+
+	// Корзина пуста, следующий запрос через секунду
+	result, err := store.Take(context.Background(), "alice", limit)
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+	assert.Equal(t, time.Second, result.RetryAfter)
+	assert.Equal(t, 3*time.Second, result.Reset)
+
+	// Корзины клиентов независимы
+	result, err = store.Take(context.Background(), "bob", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+
+	// За секунду корзина пополняется на один запрос
+	now = now.Add(time.Second)
+	result, err = store.Take(context.Background(), "alice", limit)
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	assert.Equal(t, 0, result.Remaining)
+
+	// Заполнившиеся корзины удаляются
+	now = now.Add(time.Hour)
+	store.sweep(now)
+	assert.Empty(t, store.buckets)
+}
+
+func TestLimiter(t *testing.T) {
+	limiter := NewLimiter(Config{Writes: Limit{Requests: 1, Period: time.Minute}}, NewMemoryStore())
+
+	// Группа без лимита не ограничена
+	for i := 0; i < 3; i++ {
+		result, err := limiter.Take(context.Background(), GroupReads, "alice")
+		require.NoError(t, err)
+		assert.True(t, result.Allowed)
+		assert.Zero(t, result.Limit)
+	}
+
+	result, err := limiter.Take(context.Background(), GroupWrites, "alice")
+	require.NoError(t, err)
+	assert.True(t, result.Allowed)
+	result, err = limiter.Take(context.Background(), GroupWrites, "alice")
+	require.NoError(t, err)
+	assert.False(t, result.Allowed)
+
+	assert.True(t, Config{Bulk: Limit{Requests: 1, Period: time.Second}}.Enabled())
+	assert.True(t, Config{Auth: Limit{Requests: 1, Period: time.Second}}.Enabled())
+	assert.False(t, Config{}.Enabled())
+}
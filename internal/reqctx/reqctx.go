@@ -0,0 +1,45 @@
+// Package reqctx хранит в контексте запроса данные, нужные на всех слоях приложения
+package reqctx
+
+import (
+	"context"
+
+	"github.com/go-chi/chi/v5/middleware"
+	"go.uber.org/zap"
+)
+
+type contextKey string
+
+const (
+	loggerKey contextKey = "logger"
+	actorKey  contextKey = "actor"
+)
+
+// WithLogger : Сохранение логгера запроса в контексте
+func WithLogger(ctx context.Context, loger *zap.SugaredLogger) context.Context {
+	return context.WithValue(ctx, loggerKey, loger)
+}
+
+// Logger : Логгер запроса из контекста или fallback, если его там нет
+func Logger(ctx context.Context, fallback *zap.SugaredLogger) *zap.SugaredLogger {
+	if loger, ok := ctx.Value(loggerKey).(*zap.SugaredLogger); ok {
+		return loger
+	}
+	return fallback
+}
+
+// RequestID : Идентификатор запроса, назначенный middleware.RequestID
+func RequestID(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
+
+// WithActor : Сохранение автора запроса в контексте, он попадает в журнал ревизий песен
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// Actor : Автор запроса или пустая строка для анонимного запроса
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey).(string)
+	return actor
+}
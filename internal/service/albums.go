@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+// checkAlbumArtist : Проверка исполнителя альбома. Несуществующий исполнитель - ошибка данных запроса, а не 404
+func (s Service) checkAlbumArtist(ctx context.Context, artistID string) error {
+	_, err := s.store.ReadArtist(ctx, artistID)
+	if errors.Is(err, models.ErrNotFound) {
+		return models.NewError(models.ErrValidation, models.CodeArtistNotFound, "Album artist not found", err)
+	}
+	return err
+}
+
+// albumFromRequest : Альбом из тела запроса
+func albumFromRequest(guid string, album models.AlbumRequest) models.Album {
+	return models.Album{
+		ID:        guid,
+		Title:     album.Title,
+		ArtistID:  album.ArtistID,
+		Release:   album.Release,
+		CoverLink: album.CoverLink,
+	}
+}
+
+// CreateAlbum : Создание альбома с треками и вызов сервиса хранилища
+func (s Service) CreateAlbum(ctx context.Context, album models.AlbumRequest) (models.Album, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.CreateAlbum")
+	defer span.End()
+	loger.Debugln("Creating album in service")
+	result := models.Album{}
+
+	if err := s.checkAlbumArtist(ctx, album.ArtistID); err != nil {
+		loger.Errorf("Error checking album artist: %v", err)
+		return result, err
+	}
+
+	guid, err := s.store.CreateAlbum(ctx, albumFromRequest("", album), album.Tracks)
+	if err != nil {
+		loger.Errorf("Error creating album: %v", err)
+		return result, err
+	}
+
+	return s.GetAlbum(ctx, guid)
+}
+
+// GetAlbum : Получение альбома с треками по его ID и вызов сервиса хранилища
+func (s Service) GetAlbum(ctx context.Context, guid string) (models.Album, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetAlbum")
+	defer span.End()
+	loger.Debugln("Getting album in service")
+	result := models.Album{}
+
+	result, err := s.store.ReadAlbum(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error getting album: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// GetAlbumsList : Получение списка альбомов и вызов сервиса хранилища
+func (s Service) GetAlbumsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.AlbumsListResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetAlbumsList")
+	defer span.End()
+	loger.Debugln("Getting albums list in service")
+	result := models.AlbumsListResponse{}
+
+	_, err := strconv.Atoi(paginationOptions.Limit)
+	if err != nil {
+		loger.Errorf("Error converting limit to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Limit must be a number", err)
+	}
+	_, err = strconv.Atoi(paginationOptions.Offset)
+	if err != nil {
+		loger.Errorf("Error converting offset to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Offset must be a number", err)
+	}
+
+	result, err = s.store.GetAlbumsList(ctx, paginationOptions)
+	if err != nil {
+		loger.Errorf("Error getting albums list: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// UpdateAlbum : Обновление альбома и вызов сервиса хранилища. Треки заменяются, только если они переданы
+func (s Service) UpdateAlbum(ctx context.Context, guid string, album models.AlbumRequest) (models.Album, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.UpdateAlbum")
+	defer span.End()
+	loger.Debugln("Updating album in service")
+	result := models.Album{}
+
+	if err := s.checkAlbumArtist(ctx, album.ArtistID); err != nil {
+		loger.Errorf("Error checking album artist: %v", err)
+		return result, err
+	}
+
+	result, err := s.store.UpdateAlbum(ctx, albumFromRequest(guid, album), album.Tracks)
+	if err != nil {
+		loger.Errorf("Error updating album: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// DeleteAlbum : Удаление альбома и вызов сервиса хранилища
+func (s Service) DeleteAlbum(ctx context.Context, guid string) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.DeleteAlbum")
+	defer span.End()
+	loger.Debugln("Deleting album in service")
+
+	err := s.store.DeleteAlbum(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error deleting album: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// SetAlbumTracks : Замена и перестановка треков альбома и вызов сервиса хранилища
+func (s Service) SetAlbumTracks(ctx context.Context, guid string, tracks []string) (models.Album, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.SetAlbumTracks")
+	defer span.End()
+	loger.Debugln("Setting album tracks in service")
+	result := models.Album{}
+
+	result, err := s.store.SetAlbumTracks(ctx, guid, tracks)
+	if err != nil {
+		loger.Errorf("Error setting album tracks: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// AddAlbumTrack : Добавление песни в конец альбома и вызов сервиса хранилища
+func (s Service) AddAlbumTrack(ctx context.Context, guid string, songID string) (models.Album, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.AddAlbumTrack")
+	defer span.End()
+	loger.Debugln("Adding album track in service")
+	result := models.Album{}
+
+	result, err := s.store.AddAlbumTrack(ctx, guid, songID)
+	if err != nil {
+		loger.Errorf("Error adding album track: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+const (
+	// apiKeyPrefix : начало всех ключей API, по нему ключ легко найти в конфигурации и логах
+	apiKeyPrefix = "msk_"
+	// apiKeyBytes : длина случайной части ключа
+	apiKeyBytes = 32
+	// apiKeyShownLength : сколько первых символов ключа хранится и показывается в списке
+	apiKeyShownLength = 12
+)
+
+// generateAPIKey : Новый ключ API, его начало для списка и хэш для хранения
+func generateAPIKey() (key string, prefix string, hash string, err error) {
+	secret := make([]byte, apiKeyBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return "", "", "", err
+	}
+	key = apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+	return key, key[:apiKeyShownLength], hashAPIKey(key), nil
+}
+
+// hashAPIKey : SHA-256 ключа. Ключи случайные и длинные, поэтому соль и медленный хэш не нужны
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// CreateAPIKey : Выпуск ключа API и вызов сервиса хранилища. Ключ возвращается только в ответе
+func (s Service) CreateAPIKey(ctx context.Context, request models.APIKeyRequest) (models.APIKeyIssued, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.CreateAPIKey")
+	defer span.End()
+	loger.Debugln("Creating API key in service")
+	result := models.APIKeyIssued{}
+
+	if request.ExpiresAt != nil && !request.ExpiresAt.After(time.Now()) {
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidValue, "Key expiration must be in the future", nil)
+	}
+	// Ключ не может дать больше прав, чем у клиента, который его выпускает
+	principal, _ := auth.FromContext(ctx)
+	if scope := auth.HighestRole(request.Scopes); !principal.Role.Allows(scope) {
+		loger.Warnf("Key scope %v exceeds caller role %v", scope, principal.Role)
+		return result, models.NewError(models.ErrForbidden, models.CodeForbidden, fmt.Sprintf("Key scope %v exceeds your role %v", scope, principal.Role), nil)
+	}
+
+	key, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		loger.Errorf("Error generating API key: %v", err)
+		return result, err
+	}
+
+	result.APIKey, err = s.store.CreateAPIKey(ctx, models.APIKey{
+		Name:      request.Name,
+		Prefix:    prefix,
+		Scopes:    request.Scopes,
+		ExpiresAt: request.ExpiresAt,
+		CreatedBy: reqctx.Actor(ctx),
+	}, hash)
+	if err != nil {
+		loger.Errorf("Error creating API key: %v", err)
+		return result, err
+	}
+	result.Key = key
+
+	return result, nil
+}
+
+// GetAPIKeysList : Получение списка ключей API и вызов сервиса хранилища
+func (s Service) GetAPIKeysList(ctx context.Context, paginationOptions models.PaginationOptions) (models.APIKeysListResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetAPIKeysList")
+	defer span.End()
+	loger.Debugln("Getting API keys list in service")
+	result := models.APIKeysListResponse{}
+
+	_, err := strconv.Atoi(paginationOptions.Limit)
+	if err != nil {
+		loger.Errorf("Error converting limit to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Limit must be a number", err)
+	}
+	_, err = strconv.Atoi(paginationOptions.Offset)
+	if err != nil {
+		loger.Errorf("Error converting offset to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Offset must be a number", err)
+	}
+
+	result, err = s.store.GetAPIKeysList(ctx, paginationOptions)
+	if err != nil {
+		loger.Errorf("Error getting API keys list: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// RotateAPIKey : Выпуск нового ключа вместо прежнего с теми же ролями и сроком действия
+func (s Service) RotateAPIKey(ctx context.Context, guid string) (models.APIKeyIssued, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.RotateAPIKey")
+	defer span.End()
+	loger.Debugln("Rotating API key in service")
+	result := models.APIKeyIssued{}
+
+	key, prefix, hash, err := generateAPIKey()
+	if err != nil {
+		loger.Errorf("Error generating API key: %v", err)
+		return result, err
+	}
+
+	result.APIKey, err = s.store.RotateAPIKey(ctx, guid, prefix, hash)
+	if err != nil {
+		loger.Errorf("Error rotating API key: %v", err)
+		return result, err
+	}
+	result.Key = key
+
+	return result, nil
+}
+
+// RevokeAPIKey : Отзыв ключа API и вызов сервиса хранилища
+func (s Service) RevokeAPIKey(ctx context.Context, guid string) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.RevokeAPIKey")
+	defer span.End()
+	loger.Debugln("Revoking API key in service")
+
+	err := s.store.RevokeAPIKey(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error revoking API key: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// AuthenticateAPIKey : Проверка ключа API из запроса. Неизвестный, отозванный и просроченный ключ
+// одинаково отклоняются, чтобы по ответу нельзя было узнать, существовал ли ключ
+func (s Service) AuthenticateAPIKey(ctx context.Context, key string) (models.APIKey, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.AuthenticateAPIKey")
+	defer span.End()
+	loger.Debugln("Authenticating API key in service")
+
+	result, err := s.store.UseAPIKey(ctx, hashAPIKey(key))
+	if errors.Is(err, models.ErrNotFound) {
+		return result, models.NewError(models.ErrUnauthorized, models.CodeUnauthorized, "API key is not valid", err)
+	}
+	if err != nil {
+		loger.Errorf("Error authenticating API key: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
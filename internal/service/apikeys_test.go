@@ -0,0 +1,39 @@
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/models"
+)
+
+func TestGenerateAPIKey(t *testing.T) {
+	key, prefix, hash, err := generateAPIKey()
+	require.NoError(t, err)
+
+	assert.True(t, strings.HasPrefix(key, apiKeyPrefix))
+	assert.Equal(t, key[:apiKeyShownLength], prefix)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hashAPIKey(key), hash)
+	assert.NotContains(t, hash, key)
+
+	other, _, otherHash, err := generateAPIKey()
+	require.NoError(t, err)
+	assert.NotEqual(t, key, other)
+	assert.NotEqual(t, hash, otherHash)
+}
+
+func TestCreateAPIKeyScopeAboveRole(t *testing.T) {
+	s := NewService(nil, nil, nil, zap.NewNop().Sugar())
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "bob", Role: auth.RoleEditor})
+
+	// Редактор не может выпустить ключ администратора, хранилище не вызывается
+	_, err := s.CreateAPIKey(ctx, models.APIKeyRequest{Name: "deploy", Scopes: []string{"reader", "admin"}})
+	assert.ErrorIs(t, err, models.ErrForbidden)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"maps"
+	"strconv"
+	"strings"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+// artistKey : Ключ сравнения исполнителей, совпадающий с уникальным индексом таблицы artists
+func artistKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// CreateArtist : Создание исполнителя и вызов сервиса хранилища
+func (s Service) CreateArtist(ctx context.Context, artist models.ArtistRequest) (models.Artist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.CreateArtist")
+	defer span.End()
+	loger.Debugln("Creating artist in service")
+	result := models.Artist{}
+
+	result, err := s.store.CreateArtist(ctx, artist.Name)
+	if err != nil {
+		loger.Errorf("Error creating artist: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// GetArtist : Получение исполнителя по его ID и вызов сервиса хранилища
+func (s Service) GetArtist(ctx context.Context, guid string) (models.Artist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetArtist")
+	defer span.End()
+	loger.Debugln("Getting artist in service")
+	result := models.Artist{}
+
+	result, err := s.store.ReadArtist(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error getting artist: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// GetArtistsList : Получение списка исполнителей и вызов сервиса хранилища
+func (s Service) GetArtistsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.ArtistsListResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetArtistsList")
+	defer span.End()
+	loger.Debugln("Getting artists list in service")
+	result := models.ArtistsListResponse{}
+
+	_, err := strconv.Atoi(paginationOptions.Limit)
+	if err != nil {
+		loger.Errorf("Error converting limit to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Limit must be a number", err)
+	}
+	_, err = strconv.Atoi(paginationOptions.Offset)
+	if err != nil {
+		loger.Errorf("Error converting offset to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Offset must be a number", err)
+	}
+
+	result, err = s.store.GetArtistsList(ctx, paginationOptions)
+	if err != nil {
+		loger.Errorf("Error getting artists list: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// UpdateArtist : Переименование исполнителя и вызов сервиса хранилища
+func (s Service) UpdateArtist(ctx context.Context, artist models.Artist) (models.Artist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.UpdateArtist")
+	defer span.End()
+	loger.Debugln("Updating artist in service")
+	result := models.Artist{}
+
+	result, err := s.store.UpdateArtist(ctx, artist)
+	if err != nil {
+		loger.Errorf("Error updating artist: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// DeleteArtist : Удаление исполнителя без песен и вызов сервиса хранилища
+func (s Service) DeleteArtist(ctx context.Context, guid string) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.DeleteArtist")
+	defer span.End()
+	loger.Debugln("Deleting artist in service")
+
+	err := s.store.DeleteArtist(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error deleting artist: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// GetArtistSongs : Получение списка песен исполнителя с теми же сортировкой, фильтрами и пагинацией,
+// что и в общем списке песен
+func (s Service) GetArtistSongs(ctx context.Context, guid string, sortOptions models.SortOptions, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsListResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetArtistSongs")
+	defer span.End()
+	loger.Debugln("Getting artist songs in service")
+	result := models.SongsListResponse{}
+
+	// Для несуществующего исполнителя возвращается 404, а не пустой список
+	if _, err := s.store.ReadArtist(ctx, guid); err != nil {
+		loger.Errorf("Error getting artist: %v", err)
+		return result, err
+	}
+
+	artistFilter := maps.Clone(filterOptions)
+	if artistFilter == nil {
+		artistFilter = make(map[string]string)
+	}
+	artistFilter["artist"] = guid
+
+	return s.GetSongsList(ctx, sortOptions, paginationOptions, artistFilter)
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// cacheStatsProvider : хранилище с кэшем чтения
+type cacheStatsProvider interface {
+	CacheStats() []models.CacheStats
+}
+
+// GetCacheStats : Счетчики кэша чтения песен, если хранилище его использует
+func (s Service) GetCacheStats(ctx context.Context) models.CacheStatsResponse {
+	loger := reqctx.Logger(ctx, s.loger)
+	loger.Debugln("Getting cache stats in service")
+
+	provider, ok := s.store.(cacheStatsProvider)
+	if !ok {
+		return models.CacheStatsResponse{Caches: []models.CacheStats{}}
+	}
+	return models.CacheStatsResponse{Enabled: true, Caches: provider.CacheStats()}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"EffectiveMobile/internal/models"
+)
+
+// coupletRange : диапазон номеров куплетов, включая границы
+type coupletRange struct {
+	from int
+	to   int
+}
+
+// parseCouplets : Разбор номеров куплетов вида 2, 2-4 или 1,3-4. Куплеты нумеруются с 1
+func parseCouplets(coupletId string) ([]coupletRange, error) {
+	var result []coupletRange
+
+	for _, part := range strings.Split(coupletId, ",") {
+		part = strings.TrimSpace(part)
+
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := parseCoupletNumber(from)
+		if err != nil {
+			return nil, err
+		}
+		last := first
+		if isRange {
+			if last, err = parseCoupletNumber(to); err != nil {
+				return nil, err
+			}
+		}
+		if first > last {
+			return nil, models.NewError(models.ErrValidation, models.CodeInvalidCouplet,
+				fmt.Sprintf("Couplet range %v must be ascending", part), nil)
+		}
+
+		result = append(result, coupletRange{from: first, to: last})
+	}
+
+	return result, nil
+}
+
+// parseCoupletNumber : Разбор номера куплета, номер должен быть положительным числом
+func parseCoupletNumber(value string) (int, error) {
+	number, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil || number < 1 {
+		return 0, models.NewError(models.ErrValidation, models.CodeInvalidCouplet,
+			"Couplet_id must be a positive number, a range like 2-4 or a list like 1,3", err)
+	}
+	return number, nil
+}
+
+// selectCouplets : Выбор куплетов по номерам. Номер за пределами текста песни - ошибка not found
+func selectCouplets(sections []models.LyricsSection, ranges []coupletRange) ([]models.SongCouplet, error) {
+	var result []models.SongCouplet
+
+	for _, couplets := range ranges {
+		if couplets.to > len(sections) {
+			return nil, models.NewError(models.ErrNotFound, models.CodeCoupletNotFound,
+				fmt.Sprintf("Couplet %v not found, song has %v couplets", couplets.to, len(sections)), nil)
+		}
+		for number := couplets.from; number <= couplets.to; number++ {
+			result = append(result, models.SongCouplet{
+				Number: number,
+				Text:   strings.Join(sections[number-1].Lines, "\n"),
+			})
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestParseCouplets(t *testing.T) {
+	ranges, err := parseCouplets("2")
+	require.NoError(t, err)
+	assert.Equal(t, []coupletRange{{from: 2, to: 2}}, ranges)
+
+	ranges, err = parseCouplets("2-4")
+	require.NoError(t, err)
+	assert.Equal(t, []coupletRange{{from: 2, to: 4}}, ranges)
+
+	ranges, err = parseCouplets("1, 3-4")
+	require.NoError(t, err)
+	assert.Equal(t, []coupletRange{{from: 1, to: 1}, {from: 3, to: 4}}, ranges)
+
+	for _, coupletId := range []string{"", "0", "-1", "a", "1,", "4-2", "1-2-3", "1-"} {
+		_, err = parseCouplets(coupletId)
+		var domainErr *models.DomainError
+		require.True(t, errors.As(err, &domainErr), coupletId)
+		assert.Equal(t, models.ErrValidation, domainErr.Kind, coupletId)
+	}
+}
+
+func TestSelectCouplets(t *testing.T) {
+	sections := []models.LyricsSection{
+		{Type: SectionVerse, Index: 1, Lines: []string{"First", "line"}},
+		{Type: SectionChorus, Index: 1, Lines: []string{"Chorus"}},
+		{Type: SectionVerse, Index: 2, Lines: []string{"Second"}},
+	}
+
+	couplets, err := selectCouplets(sections, []coupletRange{{from: 1, to: 1}, {from: 2, to: 3}})
+	require.NoError(t, err)
+	assert.Equal(t, []models.SongCouplet{
+		{Number: 1, Text: "First\nline"},
+		{Number: 2, Text: "Chorus"},
+		{Number: 3, Text: "Second"},
+	}, couplets)
+
+	_, err = selectCouplets(sections, []coupletRange{{from: 3, to: 4}})
+	var domainErr *models.DomainError
+	require.True(t, errors.As(err, &domainErr))
+	assert.Equal(t, models.ErrNotFound, domainErr.Kind)
+	assert.Equal(t, models.CodeCoupletNotFound, domainErr.Code)
+
+	_, err = selectCouplets(nil, []coupletRange{{from: 1, to: 1}})
+	assert.Error(t, err)
+}
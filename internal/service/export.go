@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+// ExportSongs : Потоковая выгрузка песен и вызов сервиса хранилища, fn вызывается для каждой песни
+func (s Service) ExportSongs(ctx context.Context, sortOptions models.SortOptions, filterOptions map[string]string, fn func(song models.Song) error) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.ExportSongs")
+	defer span.End()
+	loger.Debugln("Exporting songs in service")
+
+	loger.Debugf("SortOptions: %v", sortOptions)
+	loger.Debugf("FilterOptions: %v", filterOptions)
+
+	if err := s.store.ExportSongs(ctx, sortOptions, filterOptions, fn); err != nil {
+		loger.Errorf("Error exporting songs: %v", err)
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+// ImportSongs : Пакетный импорт песен. Непрошедшие проверку строки и повторы внутри файла
+// не передаются в хранилище, для каждой строки в отчете указывается результат.
+// Исполнители сравниваются так же, как в базе данных, без учета регистра
+func (s Service) ImportSongs(ctx context.Context, rows []models.ImportRow, dryRun bool) (models.ImportReport, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.ImportSongs")
+	defer span.End()
+	loger.Debugln("Importing songs in service")
+	result := models.ImportReport{
+		DryRun: dryRun,
+		Rows:   make([]models.ImportRowResult, len(rows)),
+	}
+
+	songs := make([]models.Song, 0, len(rows))
+	seenKeys := make(map[[2]string]bool, len(rows))
+	seenIDs := make(map[string]bool, len(rows))
+
+	for i, row := range rows {
+		result.Rows[i] = models.ImportRowResult{Row: row.Row}
+
+		key := [2]string{row.Song.Name, artistKey(row.Song.Artist)}
+		switch {
+		case row.Error != "":
+			result.Rows[i].Status = models.ImportInvalid
+			result.Rows[i].Error = row.Error
+		case seenKeys[key] || (row.Song.ID != "" && seenIDs[row.Song.ID]):
+			result.Rows[i].Status = models.ImportDuplicate
+		default:
+			seenKeys[key] = true
+			if row.Song.ID != "" {
+				seenIDs[row.Song.ID] = true
+			}
+			row.Song.Lyrics = ParseLyrics(row.Song.Text)
+			songs = append(songs, row.Song)
+		}
+	}
+
+	created, err := s.store.ImportSongs(ctx, songs, dryRun)
+	if err != nil {
+		loger.Errorf("Error importing songs: %v", err)
+		return result, err
+	}
+
+	createdIDs := make(map[[2]string]string, len(created))
+	for _, song := range created {
+		createdIDs[[2]string{song.Name, artistKey(song.Artist)}] = song.ID
+	}
+
+	for i, row := range rows {
+		if result.Rows[i].Status != "" {
+			continue
+		}
+		id, ok := createdIDs[[2]string{row.Song.Name, artistKey(row.Song.Artist)}]
+		if !ok {
+			result.Rows[i].Status = models.ImportDuplicate
+			continue
+		}
+		result.Rows[i].Status = models.ImportCreated
+		if !dryRun {
+			result.Rows[i].ID = id
+		}
+	}
+
+	for _, row := range result.Rows {
+		switch row.Status {
+		case models.ImportCreated:
+			result.Created++
+		case models.ImportDuplicate:
+			result.Duplicate++
+		case models.ImportInvalid:
+			result.Invalid++
+		}
+	}
+
+	return result, nil
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"EffectiveMobile/internal/models"
+)
+
+// Типы частей текста песни
+const (
+	SectionVerse     = "verse"
+	SectionChorus    = "chorus"
+	SectionPreChorus = "pre-chorus"
+	SectionBridge    = "bridge"
+	SectionIntro     = "intro"
+	SectionOutro     = "outro"
+	SectionHook      = "hook"
+)
+
+var (
+	// sectionMarker : строка-метка части вида [Chorus], [Verse 2], [Chorus: исполнитель]
+	sectionMarker = regexp.MustCompile(`^\[\s*([^\]:]*?)\s*(\d+)?\s*(?::[^\]]*)?\]$`)
+
+	// sectionTypes : синонимы названий частей в метках
+	sectionTypes = map[string]string{
+		"verse":      SectionVerse,
+		"куплет":     SectionVerse,
+		"chorus":     SectionChorus,
+		"refrain":    SectionChorus,
+		"припев":     SectionChorus,
+		"pre-chorus": SectionPreChorus,
+		"prechorus":  SectionPreChorus,
+		"бридж":      SectionBridge,
+		"bridge":     SectionBridge,
+		"intro":      SectionIntro,
+		"вступление": SectionIntro,
+		"outro":      SectionOutro,
+		"hook":       SectionHook,
+	}
+)
+
+// lyricsSection : часть текста в процессе разбора. marked - тип задан меткой, number - номер из метки
+type lyricsSection struct {
+	kind   string
+	marked bool
+	number int
+	lines  []string
+}
+
+// NormalizeLyrics : Приведение переводов строк к \n, в том числе записанных в тексте как литерал "\n"
+func NormalizeLyrics(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+	return strings.ReplaceAll(text, `\n`, "\n")
+}
+
+// ParseLyrics : Разбор текста песни на части. Части разделяются пустыми строками или метками вида [Chorus].
+// Метка без строк повторяет последнюю часть того же типа, неразмеченная часть, повторяющая
+// предыдущую, считается припевом. Остальные неразмеченные части считаются куплетами
+func ParseLyrics(text string) []models.LyricsSection {
+	var parsed []*lyricsSection
+	var current *lyricsSection
+
+	closeSection := func() {
+		if current == nil {
+			return
+		}
+		if len(current.lines) == 0 && current.marked {
+			for i := len(parsed) - 1; i >= 0; i-- {
+				if parsed[i].kind == current.kind {
+					current.lines = parsed[i].lines
+					break
+				}
+			}
+		}
+		if len(current.lines) > 0 {
+			parsed = append(parsed, current)
+		}
+		current = nil
+	}
+
+	for _, line := range strings.Split(NormalizeLyrics(text), "\n") {
+		line = strings.TrimSpace(line)
+
+		if line == "" {
+			closeSection()
+			continue
+		}
+
+		if match := sectionMarker.FindStringSubmatch(line); match != nil {
+			closeSection()
+			current = &lyricsSection{kind: sectionType(match[1]), marked: true}
+			current.number, _ = strconv.Atoi(match[2])
+			continue
+		}
+
+		if current == nil {
+			current = &lyricsSection{kind: SectionVerse}
+		}
+		current.lines = append(current.lines, line)
+	}
+	closeSection()
+
+	// Повторяющиеся неразмеченные части - припев
+	for i, section := range parsed {
+		if section.marked {
+			continue
+		}
+		for _, previous := range parsed[:i] {
+			if !slices.Equal(previous.lines, section.lines) {
+				continue
+			}
+			if previous.kind == SectionChorus || !previous.marked {
+				previous.kind = SectionChorus
+				section.kind = SectionChorus
+			} else {
+				section.kind = previous.kind
+			}
+			break
+		}
+	}
+
+	result := make([]models.LyricsSection, 0, len(parsed))
+	counters := make(map[string]int)
+	for _, section := range parsed {
+		counters[section.kind]++
+		index := counters[section.kind]
+		if section.number > 0 {
+			index = section.number
+			counters[section.kind] = section.number
+		}
+		result = append(result, models.LyricsSection{
+			Type:  section.kind,
+			Index: index,
+			Lines: section.lines,
+		})
+	}
+	return result
+}
+
+// sectionType : Тип части по названию из метки, неизвестные названия сохраняются как есть
+func sectionType(name string) string {
+	name = strings.Join(strings.Fields(strings.ToLower(name)), "-")
+	if kind, ok := sectionTypes[name]; ok {
+		return kind
+	}
+	if name == "" {
+		return SectionVerse
+	}
+	return name
+}
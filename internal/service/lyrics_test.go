@@ -0,0 +1,53 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestNormalizeLyrics(t *testing.T) {
+	assert.Equal(t, "a\nb\nc\nd", NormalizeLyrics("a\r\nb\rc\\nd"))
+}
+
+func TestParseLyricsStanzas(t *testing.T) {
+	text := "Ooh baby, don't you know I suffer?\\nOoh baby, can you hear me moan?\\n\\nOoh\\nYou set my soul alight\r\n\r\n\r\nThird"
+
+	assert.Equal(t, []models.LyricsSection{
+		{Type: SectionVerse, Index: 1, Lines: []string{"Ooh baby, don't you know I suffer?", "Ooh baby, can you hear me moan?"}},
+		{Type: SectionVerse, Index: 2, Lines: []string{"Ooh", "You set my soul alight"}},
+		{Type: SectionVerse, Index: 3, Lines: []string{"Third"}},
+	}, ParseLyrics(text))
+}
+
+func TestParseLyricsMarkers(t *testing.T) {
+	text := "[Intro]\nOoh\n[Verse 1]\nFirst line\nSecond line\n\n[Chorus: Muse]\nChorus line\n\n[Verse 2]\nThird line\n\n[Chorus]\n\n[Bridge]\nBridge line\n[Припев]\n"
+
+	assert.Equal(t, []models.LyricsSection{
+		{Type: SectionIntro, Index: 1, Lines: []string{"Ooh"}},
+		{Type: SectionVerse, Index: 1, Lines: []string{"First line", "Second line"}},
+		{Type: SectionChorus, Index: 1, Lines: []string{"Chorus line"}},
+		{Type: SectionVerse, Index: 2, Lines: []string{"Third line"}},
+		{Type: SectionChorus, Index: 2, Lines: []string{"Chorus line"}},
+		{Type: SectionBridge, Index: 1, Lines: []string{"Bridge line"}},
+		{Type: SectionChorus, Index: 3, Lines: []string{"Chorus line"}},
+	}, ParseLyrics(text))
+}
+
+func TestParseLyricsRepeatedChorus(t *testing.T) {
+	text := "Verse one\n\nSame chorus\nAgain\n\nVerse two\n\nSame chorus\nAgain"
+
+	assert.Equal(t, []models.LyricsSection{
+		{Type: SectionVerse, Index: 1, Lines: []string{"Verse one"}},
+		{Type: SectionChorus, Index: 1, Lines: []string{"Same chorus", "Again"}},
+		{Type: SectionVerse, Index: 2, Lines: []string{"Verse two"}},
+		{Type: SectionChorus, Index: 2, Lines: []string{"Same chorus", "Again"}},
+	}, ParseLyrics(text))
+}
+
+func TestParseLyricsEmpty(t *testing.T) {
+	assert.Empty(t, ParseLyrics(""))
+	assert.Empty(t, ParseLyrics("\\n\\n \n[Chorus]"))
+}
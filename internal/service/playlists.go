@@ -0,0 +1,211 @@
+package service
+
+import (
+	"context"
+	"strconv"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+// CreatePlaylist : Создание плейлиста и вызов сервиса хранилища. Владельцем становится автор запроса
+func (s Service) CreatePlaylist(ctx context.Context, playlist models.PlaylistRequest) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.CreatePlaylist")
+	defer span.End()
+	loger.Debugln("Creating playlist in service")
+	result := models.Playlist{}
+
+	guid, err := s.store.CreatePlaylist(ctx, models.Playlist{
+		Name:        playlist.Name,
+		Description: playlist.Description,
+		Owner:       reqctx.Actor(ctx),
+	})
+	if err != nil {
+		loger.Errorf("Error creating playlist: %v", err)
+		return result, err
+	}
+
+	return s.GetPlaylist(ctx, guid)
+}
+
+// checkPlaylistOwner : Изменять плейлист и его записи может только владелец или администратор
+func (s Service) checkPlaylistOwner(ctx context.Context, guid string) error {
+	if principal, _ := auth.FromContext(ctx); principal.Role.Allows(auth.RoleAdmin) {
+		return nil
+	}
+
+	playlist, err := s.store.ReadPlaylist(ctx, guid)
+	if err != nil {
+		return err
+	}
+	if playlist.Owner == "" || playlist.Owner != reqctx.Actor(ctx) {
+		return models.NewError(models.ErrForbidden, models.CodeForbidden, "Only the playlist owner can change it", nil)
+	}
+	return nil
+}
+
+// GetPlaylist : Получение плейлиста с записями по его ID и вызов сервиса хранилища
+func (s Service) GetPlaylist(ctx context.Context, guid string) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetPlaylist")
+	defer span.End()
+	loger.Debugln("Getting playlist in service")
+	result := models.Playlist{}
+
+	result, err := s.store.ReadPlaylist(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error getting playlist: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// GetPlaylistsList : Получение списка плейлистов и вызов сервиса хранилища
+func (s Service) GetPlaylistsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.PlaylistsListResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetPlaylistsList")
+	defer span.End()
+	loger.Debugln("Getting playlists list in service")
+	result := models.PlaylistsListResponse{}
+
+	_, err := strconv.Atoi(paginationOptions.Limit)
+	if err != nil {
+		loger.Errorf("Error converting limit to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Limit must be a number", err)
+	}
+	_, err = strconv.Atoi(paginationOptions.Offset)
+	if err != nil {
+		loger.Errorf("Error converting offset to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Offset must be a number", err)
+	}
+
+	result, err = s.store.GetPlaylistsList(ctx, paginationOptions)
+	if err != nil {
+		loger.Errorf("Error getting playlists list: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// UpdatePlaylist : Изменение названия и описания плейлиста и вызов сервиса хранилища
+func (s Service) UpdatePlaylist(ctx context.Context, guid string, playlist models.PlaylistRequest) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.UpdatePlaylist")
+	defer span.End()
+	loger.Debugln("Updating playlist in service")
+	result := models.Playlist{}
+
+	err := s.checkPlaylistOwner(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error checking playlist owner: %v", err)
+		return result, err
+	}
+
+	result, err = s.store.UpdatePlaylist(ctx, models.Playlist{
+		ID:          guid,
+		Name:        playlist.Name,
+		Description: playlist.Description,
+	})
+	if err != nil {
+		loger.Errorf("Error updating playlist: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// DeletePlaylist : Удаление плейлиста и вызов сервиса хранилища
+func (s Service) DeletePlaylist(ctx context.Context, guid string) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.DeletePlaylist")
+	defer span.End()
+	loger.Debugln("Deleting playlist in service")
+
+	err := s.checkPlaylistOwner(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error checking playlist owner: %v", err)
+		return err
+	}
+
+	err = s.store.DeletePlaylist(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error deleting playlist: %v", err)
+		return err
+	}
+
+	return nil
+}
+
+// AddPlaylistEntry : Вставка песни в плейлист и вызов сервиса хранилища
+func (s Service) AddPlaylistEntry(ctx context.Context, guid string, entry models.PlaylistEntryRequest) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.AddPlaylistEntry")
+	defer span.End()
+	loger.Debugln("Adding playlist entry in service")
+	result := models.Playlist{}
+
+	err := s.checkPlaylistOwner(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error checking playlist owner: %v", err)
+		return result, err
+	}
+
+	result, err = s.store.AddPlaylistEntry(ctx, guid, entry.SongID, entry.Position)
+	if err != nil {
+		loger.Errorf("Error adding playlist entry: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// RemovePlaylistEntry : Удаление записи плейлиста по позиции и вызов сервиса хранилища
+func (s Service) RemovePlaylistEntry(ctx context.Context, guid string, position int) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.RemovePlaylistEntry")
+	defer span.End()
+	loger.Debugln("Removing playlist entry in service")
+	result := models.Playlist{}
+
+	err := s.checkPlaylistOwner(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error checking playlist owner: %v", err)
+		return result, err
+	}
+
+	result, err = s.store.RemovePlaylistEntry(ctx, guid, position)
+	if err != nil {
+		loger.Errorf("Error removing playlist entry: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// MovePlaylistEntry : Перемещение записи плейлиста на другую позицию и вызов сервиса хранилища
+func (s Service) MovePlaylistEntry(ctx context.Context, guid string, from int, to int) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.MovePlaylistEntry")
+	defer span.End()
+	loger.Debugln("Moving playlist entry in service")
+	result := models.Playlist{}
+
+	err := s.checkPlaylistOwner(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error checking playlist owner: %v", err)
+		return result, err
+	}
+
+	result, err = s.store.MovePlaylistEntry(ctx, guid, from, to)
+	if err != nil {
+		loger.Errorf("Error moving playlist entry: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
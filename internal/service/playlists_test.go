@@ -0,0 +1,72 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/storage"
+)
+
+// playlistStorage : хранилище с одним плейлистом в памяти
+type playlistStorage struct {
+	storage.SongStorage
+	playlist models.Playlist
+}
+
+func (s *playlistStorage) ReadPlaylist(ctx context.Context, guid string) (models.Playlist, error) {
+	if guid != s.playlist.ID {
+		return models.Playlist{}, models.NewError(models.ErrNotFound, "playlist_not_found", "Playlist not found", nil)
+	}
+	return s.playlist, nil
+}
+
+func (s *playlistStorage) UpdatePlaylist(ctx context.Context, playlist models.Playlist) (models.Playlist, error) {
+	s.playlist.Name = playlist.Name
+	return s.playlist, nil
+}
+
+func (s *playlistStorage) DeletePlaylist(ctx context.Context, guid string) error {
+	s.playlist = models.Playlist{}
+	return nil
+}
+
+func (s *playlistStorage) MovePlaylistEntry(ctx context.Context, guid string, from int, to int) (models.Playlist, error) {
+	return s.playlist, nil
+}
+
+// asUser : Контекст запроса клиента subject с ролью role
+func asUser(subject string, role auth.Role) context.Context {
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: subject, Role: role})
+	return reqctx.WithActor(ctx, subject)
+}
+
+func TestPlaylistOwner(t *testing.T) {
+	store := &playlistStorage{playlist: models.Playlist{ID: "1", Name: "Mine", Owner: "alice"}}
+	s := NewService(store, nil, nil, zap.NewNop().Sugar())
+	bob := asUser("bob", auth.RoleEditor)
+
+	// Другой редактор не может изменить чужой плейлист
+	_, err := s.UpdatePlaylist(bob, "1", models.PlaylistRequest{Name: "Stolen"})
+	assert.ErrorIs(t, err, models.ErrForbidden)
+	_, err = s.MovePlaylistEntry(bob, "1", 1, 2)
+	assert.ErrorIs(t, err, models.ErrForbidden)
+	assert.ErrorIs(t, s.DeletePlaylist(bob, "1"), models.ErrForbidden)
+	assert.Equal(t, "Mine", store.playlist.Name)
+
+	// Владелец и администратор могут
+	result, err := s.UpdatePlaylist(asUser("alice", auth.RoleEditor), "1", models.PlaylistRequest{Name: "Renamed"})
+	require.NoError(t, err)
+	assert.Equal(t, "Renamed", result.Name)
+	_, err = s.MovePlaylistEntry(asUser("root", auth.RoleAdmin), "1", 1, 2)
+	assert.NoError(t, err)
+
+	// Несуществующий плейлист остается 404
+	assert.ErrorIs(t, s.DeletePlaylist(bob, "2"), models.ErrNotFound)
+}
@@ -0,0 +1,99 @@
+package service
+
+import (
+	"context"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+// GetSongRevisions : Получение журнала ревизий песни и вызов сервиса хранилища
+func (s Service) GetSongRevisions(ctx context.Context, guid string) (models.SongRevisionsResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetSongRevisions")
+	defer span.End()
+	loger.Debugln("Getting song revisions in service")
+	result := models.SongRevisionsResponse{}
+
+	var err error
+	result.Revisions, err = s.store.GetSongRevisions(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error getting song revisions: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// DiffSongRevisions : Сравнение двух ревизий песни по полям
+func (s Service) DiffSongRevisions(ctx context.Context, guid string, from int, to int) (models.SongRevisionDiff, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.DiffSongRevisions")
+	defer span.End()
+	loger.Debugln("Diffing song revisions in service")
+	result := models.SongRevisionDiff{ID: guid, From: from, To: to}
+
+	fromRevision, err := s.store.GetSongRevision(ctx, guid, from)
+	if err != nil {
+		loger.Errorf("Error getting song revision %v: %v", from, err)
+		return result, err
+	}
+
+	toRevision, err := s.store.GetSongRevision(ctx, guid, to)
+	if err != nil {
+		loger.Errorf("Error getting song revision %v: %v", to, err)
+		return result, err
+	}
+
+	result.Changes = diffSongs(fromRevision.Song, toRevision.Song)
+	return result, nil
+}
+
+// RevertSong : Возврат песни к состоянию из ревизии. Возврат записывается в журнал как новая ревизия,
+// удаленную песню вернуть нельзя
+func (s Service) RevertSong(ctx context.Context, guid string, revision int, ifMatch []int) (models.Song, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.RevertSong")
+	defer span.End()
+	loger.Debugln("Reverting song in service")
+	result := models.Song{}
+
+	snapshot, err := s.store.GetSongRevision(ctx, guid, revision)
+	if err != nil {
+		loger.Errorf("Error getting song revision: %v", err)
+		return result, err
+	}
+
+	snapshot.Song.Lyrics = ParseLyrics(snapshot.Song.Text)
+
+	result, err = s.store.UpdateSong(ctx, snapshot.Song, ifMatch)
+	if err != nil {
+		loger.Errorf("Error reverting song: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// diffSongs : Список полей, значения которых отличаются в двух снимках песни
+func diffSongs(from models.Song, to models.Song) []models.FieldChange {
+	fields := []struct {
+		name     string
+		from, to string
+	}{
+		{"song", from.Name, to.Name},
+		{"group", from.Artist, to.Artist},
+		{"releaseDate", from.Release, to.Release},
+		{"text", from.Text, to.Text},
+		{"link", from.Link, to.Link},
+	}
+
+	changes := []models.FieldChange{}
+	for _, field := range fields {
+		if field.from != field.to {
+			changes = append(changes, models.FieldChange{Field: field.name, From: field.from, To: field.to})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,28 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestDiffSongs(t *testing.T) {
+	from := models.Song{
+		Name:    "Supermassive Black Hole",
+		Artist:  "Muse",
+		Release: "16.07.2006",
+		Text:    "Ooh baby, don't you know I suffer?",
+	}
+	to := from
+	to.Text = ""
+	to.Link = "https://www.youtube.com/watch?v=Xsp3_a-PMTw"
+
+	assert.Equal(t, []models.FieldChange{
+		{Field: "text", From: "Ooh baby, don't you know I suffer?", To: ""},
+		{Field: "link", From: "", To: "https://www.youtube.com/watch?v=Xsp3_a-PMTw"},
+	}, diffSongs(from, to))
+
+	assert.Empty(t, diffSongs(from, from))
+}
@@ -1,48 +1,127 @@
 package service
 
 import (
+	"context"
+	"errors"
 	"strconv"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 
+	"EffectiveMobile/internal/enrichment"
 	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/pagetoken"
+	"EffectiveMobile/internal/reqctx"
 	"EffectiveMobile/internal/storage"
+	"EffectiveMobile/internal/tracing"
 )
 
-var err error
-
 type Service struct {
-	store *storage.Storage
-	loger *zap.SugaredLogger
+	store    storage.SongStorage
+	enricher *enrichment.Client
+	tokens   *pagetoken.Signer
+	loger    *zap.SugaredLogger
 	SongService
 }
 
 type SongService interface {
-	CreateSong(song models.SongRequest, reqID string) (string, error)
-	ReadSong(guid string, reqID string) (models.Song, error)
-	UpdateSong(song models.Song, reqID string) (models.Song, error)
-	DeleteSong(guid string, reqID string) (models.SongResponse, error)
-	GetSongInfo(song models.SongRequest, reqID string) (models.SongInfoResponse, error)
-	GetSongsList(reqID string) (models.SongsListResponse, error)
-	GetSongCouplet(guid string, coupletId string, reqID string) (models.SongVerseResponse, error)
+	CreateSong(ctx context.Context, song models.SongRequest) (string, error)
+	ReadSong(ctx context.Context, guid string) (models.Song, error)
+	UpdateSong(ctx context.Context, song models.Song, ifMatch []int) (models.Song, error)
+	PatchSong(ctx context.Context, guid string, patch models.SongPatch, ifMatch []int) (models.Song, error)
+	DeleteSong(ctx context.Context, guid string, ifMatch []int) (models.SongResponse, error)
+	GetSongInfo(ctx context.Context, song models.SongRequest) (models.SongInfoResponse, error)
+	GetSongsList(ctx context.Context, sortOptions models.SortOptions, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsListResponse, error)
+	GetSongCouplet(ctx context.Context, guid string, coupletId string) (models.SongVerseResponse, error)
+	GetSongLyrics(ctx context.Context, guid string) (models.SongLyricsResponse, error)
+	SearchSongs(ctx context.Context, text string, lang string, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsSearchResponse, error)
+	GetSongRevisions(ctx context.Context, guid string) (models.SongRevisionsResponse, error)
+	DiffSongRevisions(ctx context.Context, guid string, from int, to int) (models.SongRevisionDiff, error)
+	RevertSong(ctx context.Context, guid string, revision int, ifMatch []int) (models.Song, error)
+	GetTrash(ctx context.Context, paginationOptions models.PaginationOptions) (models.TrashResponse, error)
+	RestoreSong(ctx context.Context, guid string) (models.Song, error)
+	PurgeTrash(ctx context.Context, retention time.Duration) (int64, error)
+	ImportSongs(ctx context.Context, rows []models.ImportRow, dryRun bool) (models.ImportReport, error)
+	ExportSongs(ctx context.Context, sortOptions models.SortOptions, filterOptions map[string]string, fn func(song models.Song) error) error
+	CreateArtist(ctx context.Context, artist models.ArtistRequest) (models.Artist, error)
+	GetArtist(ctx context.Context, guid string) (models.Artist, error)
+	GetArtistsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.ArtistsListResponse, error)
+	UpdateArtist(ctx context.Context, artist models.Artist) (models.Artist, error)
+	DeleteArtist(ctx context.Context, guid string) error
+	GetArtistSongs(ctx context.Context, guid string, sortOptions models.SortOptions, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsListResponse, error)
+	CreateAlbum(ctx context.Context, album models.AlbumRequest) (models.Album, error)
+	GetAlbum(ctx context.Context, guid string) (models.Album, error)
+	GetAlbumsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.AlbumsListResponse, error)
+	UpdateAlbum(ctx context.Context, guid string, album models.AlbumRequest) (models.Album, error)
+	DeleteAlbum(ctx context.Context, guid string) error
+	SetAlbumTracks(ctx context.Context, guid string, tracks []string) (models.Album, error)
+	AddAlbumTrack(ctx context.Context, guid string, songID string) (models.Album, error)
+	CreatePlaylist(ctx context.Context, playlist models.PlaylistRequest) (models.Playlist, error)
+	GetPlaylist(ctx context.Context, guid string) (models.Playlist, error)
+	GetPlaylistsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.PlaylistsListResponse, error)
+	UpdatePlaylist(ctx context.Context, guid string, playlist models.PlaylistRequest) (models.Playlist, error)
+	DeletePlaylist(ctx context.Context, guid string) error
+	AddPlaylistEntry(ctx context.Context, guid string, entry models.PlaylistEntryRequest) (models.Playlist, error)
+	RemovePlaylistEntry(ctx context.Context, guid string, position int) (models.Playlist, error)
+	MovePlaylistEntry(ctx context.Context, guid string, from int, to int) (models.Playlist, error)
+	CreateAPIKey(ctx context.Context, request models.APIKeyRequest) (models.APIKeyIssued, error)
+	GetAPIKeysList(ctx context.Context, paginationOptions models.PaginationOptions) (models.APIKeysListResponse, error)
+	RotateAPIKey(ctx context.Context, guid string) (models.APIKeyIssued, error)
+	RevokeAPIKey(ctx context.Context, guid string) error
+	AuthenticateAPIKey(ctx context.Context, key string) (models.APIKey, error)
+	GetCacheStats(ctx context.Context) models.CacheStatsResponse
 }
 
-func NewService(store *storage.Storage, loger *zap.SugaredLogger) *Service {
+// NewService : enricher может быть nil, тогда песни создаются без обогащения.
+// store может быть хранилищем с кэшем чтения storage.CachedStorage
+func NewService(store storage.SongStorage, enricher *enrichment.Client, tokens *pagetoken.Signer, loger *zap.SugaredLogger) *Service {
 	return &Service{
-		store: store,
-		loger: loger,
+		store:    store,
+		enricher: enricher,
+		tokens:   tokens,
+		loger:    loger,
 	}
 }
 
-// CreateSong : Создание песни и вызов сервиса хранилища
-func (s Service) CreateSong(song models.SongRequest, reqID string) (string, error) {
-	s.loger.Debugf("RequestID: %v. Creating song in service", reqID)
+// CreateSong : Создание песни, обогащение данными из внешнего API и вызов сервиса хранилища
+func (s Service) CreateSong(ctx context.Context, song models.SongRequest) (string, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.CreateSong")
+	defer span.End()
+	loger.Debugln("Creating song in service")
 	result := models.SongResponse{}
 
-	result.ID, err = s.store.CreateSong(song, reqID)
+	newSong := models.Song{
+		Name:   song.Name,
+		Artist: song.Artist,
+	}
+
+	if s.enricher != nil {
+		detail, err := s.enricher.GetSongDetail(ctx, song.Artist, song.Name)
+		switch {
+		case errors.Is(err, enrichment.ErrBadRequest):
+			loger.Warnln("Song is unknown to music info API, creating without details")
+		case err != nil:
+			loger.Errorf("Error enriching song: %v", err)
+			return "", models.NewError(models.ErrUpstream, models.CodeMusicInfoFailed, "Music info API is unavailable", err)
+		default:
+			newSong.Text = detail.Text
+			newSong.Link = detail.Link
+			if _, err := time.Parse("02.01.2006", detail.Release); err == nil {
+				newSong.Release = detail.Release
+			} else {
+				loger.Warnf("Invalid release date from music info API: %v", detail.Release)
+			}
+		}
+	}
+
+	newSong.Lyrics = ParseLyrics(newSong.Text)
+
+	var err error
+	result.ID, err = s.store.CreateSong(ctx, newSong)
 	if err != nil {
-		s.loger.Errorf("Error creating song: %v", err)
+		loger.Errorf("Error creating song: %v", err)
 		return "", err
 	}
 
@@ -50,27 +129,57 @@ func (s Service) CreateSong(song models.SongRequest, reqID string) (string, erro
 }
 
 // ReadSong : Получение песни по ее ID и вызов сервиса хранилища
-func (s Service) ReadSong(guid string, reqID string) (models.Song, error) {
-	s.loger.Debugf("RequestID: %v. Creating song in service", reqID)
+func (s Service) ReadSong(ctx context.Context, guid string) (models.Song, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.ReadSong")
+	defer span.End()
+	loger.Debugln("Reading song in service")
+	result := models.Song{}
+
+	result, err := s.store.ReadSong(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error getting song: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// UpdateSong : Обновление песни и вызов сервиса хранилища.
+// ifMatch - допустимые версии песни из If-Match, nil - без проверки версии
+func (s Service) UpdateSong(ctx context.Context, song models.Song, ifMatch []int) (models.Song, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.UpdateSong")
+	defer span.End()
+	loger.Debugln("Updating song in service")
 	result := models.Song{}
 
-	result, err = s.store.ReadSong(guid, reqID)
+	song.Lyrics = ParseLyrics(song.Text)
+
+	result, err := s.store.UpdateSong(ctx, song, ifMatch)
 	if err != nil {
-		s.loger.Errorf("Error getting song: %v", err)
+		loger.Errorf("Error updating song: %v", err)
 		return result, err
 	}
 
 	return result, nil
 }
 
-// UpdateSong : Обновление песни и вызов сервиса хранилища
-func (s Service) UpdateSong(song models.Song, reqID string) (models.Song, error) {
-	s.loger.Debugf("RequestID: %v. Updating song in service", reqID)
+// PatchSong : Частичное обновление песни и вызов сервиса хранилища
+func (s Service) PatchSong(ctx context.Context, guid string, patch models.SongPatch, ifMatch []int) (models.Song, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.PatchSong")
+	defer span.End()
+	loger.Debugln("Patching song in service")
 	result := models.Song{}
 
-	result, err = s.store.UpdateSong(song, reqID)
+	if patch.Text.Set && !patch.Text.Null {
+		patch.Lyrics = ParseLyrics(patch.Text.Value)
+	}
+
+	result, err := s.store.PatchSong(ctx, guid, patch, ifMatch)
 	if err != nil {
-		s.loger.Errorf("Error updating song: %v", err)
+		loger.Errorf("Error patching song: %v", err)
 		return result, err
 	}
 
@@ -78,13 +187,16 @@ func (s Service) UpdateSong(song models.Song, reqID string) (models.Song, error)
 }
 
 // DeleteSong : Удаление песни и вызов сервиса хранилища
-func (s Service) DeleteSong(guid string, reqID string) (models.SongResponse, error) {
-	s.loger.Debugf("RequestID: %v. Deleting song in service", reqID)
+func (s Service) DeleteSong(ctx context.Context, guid string, ifMatch []int) (models.SongResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.DeleteSong")
+	defer span.End()
+	loger.Debugln("Deleting song in service")
 	result := models.SongResponse{}
 
-	result, err = s.store.DeleteSong(guid, reqID)
+	result, err := s.store.DeleteSong(ctx, guid, ifMatch)
 	if err != nil {
-		s.loger.Errorf("Error deleting song: %v", err)
+		loger.Errorf("Error deleting song: %v", err)
 		return result, err
 	}
 
@@ -92,13 +204,16 @@ func (s Service) DeleteSong(guid string, reqID string) (models.SongResponse, err
 }
 
 // GetSongInfo : Получение информации о песне и вызов сервиса хранилища
-func (s Service) GetSongInfo(song models.SongRequest, reqID string) (models.SongInfoResponse, error) {
-	s.loger.Debugf("RequestID: %v. Getting song info in service", reqID)
+func (s Service) GetSongInfo(ctx context.Context, song models.SongRequest) (models.SongInfoResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetSongInfo")
+	defer span.End()
+	loger.Debugln("Getting song info in service")
 	result := models.SongInfoResponse{}
 
-	result, err = s.store.GetSongInfo(song, reqID)
+	result, err := s.store.GetSongInfo(ctx, song)
 	if err != nil {
-		s.loger.Errorf("Error getting song info: %v", err)
+		loger.Errorf("Error getting song info: %v", err)
 		return result, err
 	}
 
@@ -106,73 +221,150 @@ func (s Service) GetSongInfo(song models.SongRequest, reqID string) (models.Song
 }
 
 // GetSongsList : Получение списка песен и вызов сервиса хранилища
-func (s Service) GetSongsList(reqID string, sortOptions models.SortOptions, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsListResponse, error) {
-	s.loger.Debugf("RequestID: %v. Getting songs list in service", reqID)
+func (s Service) GetSongsList(ctx context.Context, sortOptions models.SortOptions, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsListResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetSongsList")
+	defer span.End()
+	loger.Debugln("Getting songs list in service")
 	result := models.SongsListResponse{}
 
-	s.loger.Debugf("SortOptions: %v", sortOptions)
-	s.loger.Debugf("PaginationOptions: %v", paginationOptions)
-	s.loger.Debugf("FilterOptions: %v", filterOptions)
+	loger.Debugf("SortOptions: %v", sortOptions)
+	loger.Debugf("PaginationOptions: %v", paginationOptions)
+	loger.Debugf("FilterOptions: %v", filterOptions)
 
-	_, err = strconv.Atoi(paginationOptions.Limit)
+	_, err := strconv.Atoi(paginationOptions.Limit)
 	if err != nil {
-		s.loger.Errorf("Error converting limit to int: %v", err)
-		return result, err
+		loger.Errorf("Error converting limit to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Limit must be a number", err)
 	}
 
-	result, err = s.store.GetSongsList(reqID, sortOptions, paginationOptions, filterOptions)
+	if paginationOptions.PageToken != "" {
+		var cursor models.PageCursor
+		cursor, err = s.tokens.Decode(paginationOptions.PageToken)
+		if err != nil {
+			loger.Errorf("Error decoding page token: %v", err)
+			return result, models.NewError(models.ErrValidation, models.CodeInvalidPageToken, "Page token is not valid", err)
+		}
+		if cursor.Field != sortOptions.Field || !strings.EqualFold(cursor.Order, sortOptions.Order) {
+			loger.Errorf("Page token does not match sort options: %v", cursor)
+			return result, models.NewError(models.ErrValidation, models.CodeInvalidPageToken, "Page token was issued for another sort order", nil)
+		}
+		paginationOptions.Cursor = &cursor
+	}
+
+	var next *models.PageCursor
+	result, next, err = s.store.GetSongsList(ctx, sortOptions, paginationOptions, filterOptions)
 	if err != nil {
-		s.loger.Errorf("Error getting songs list: %v", err)
+		loger.Errorf("Error getting songs list: %v", err)
 		return result, err
 	}
 
+	if next != nil {
+		result.NextPageToken, err = s.tokens.Encode(*next)
+		if err != nil {
+			loger.Errorf("Error encoding page token: %v", err)
+			return result, err
+		}
+	}
+
 	return result, nil
 }
 
-// GetSongCouplet : Получение куплета песни и вызов сервиса хранилища
-func (s Service) GetSongCouplet(guid string, coupletId string, reqID string) (result models.SongVerseResponse, err error) {
-	var text string
-	var couplet int
-	var couplets []string
-	s.loger.Debugf("RequestID: %v. Getting song verses in service", reqID)
+// SearchSongs : Полнотекстовый поиск песен и вызов сервиса хранилища
+func (s Service) SearchSongs(ctx context.Context, text string, lang string, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsSearchResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.SearchSongs")
+	defer span.End()
+	loger.Debugln("Searching songs in service")
+	result := models.SongsSearchResponse{}
 
-	s.loger.Debugf("CoupletId: %v", coupletId)
+	loger.Debugf("Query: %v, lang: %v", text, lang)
+	loger.Debugf("PaginationOptions: %v", paginationOptions)
 
-	if coupletId == "" {
-		couplet = 0
-	} else {
-		s.loger.Debugf("CoupletId: %v", coupletId)
-		couplet, err = strconv.Atoi(coupletId)
-		if err != nil {
-			s.loger.Errorf("Error converting coupletId to int: %v", err)
-			return result, err
-		}
+	_, err := strconv.Atoi(paginationOptions.Limit)
+	if err != nil {
+		loger.Errorf("Error converting limit to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Limit must be a number", err)
+	}
+	_, err = strconv.Atoi(paginationOptions.Offset)
+	if err != nil {
+		loger.Errorf("Error converting offset to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Offset must be a number", err)
 	}
 
-	s.loger.Debugf("Couplet int: %v", couplet)
-	s.loger.Debugf("GUID: %v", guid)
+	result, err = s.store.SearchSongs(ctx, text, lang, paginationOptions, filterOptions)
+	if err != nil {
+		loger.Errorf("Error searching songs: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
 
-	text, err = s.store.GetSongCouplet(guid, reqID)
+// GetSongCouplet : Получение куплетов песни по номерам, диапазонам или списку номеров частей текста
+func (s Service) GetSongCouplet(ctx context.Context, guid string, coupletId string) (result models.SongVerseResponse, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetSongCouplet")
+	defer span.End()
+	loger.Debugln("Getting song verses in service")
+
+	loger.Debugf("CoupletId: %v", coupletId)
+
+	ranges, err := parseCouplets(coupletId)
 	if err != nil {
-		s.loger.Errorf("Error getting song verses: %v", err)
+		loger.Errorf("Error parsing coupletId: %v", err)
 		return result, err
 	}
 
-	couplets = strings.Split(text, "\\n\\n")
+	lyrics, err := s.GetSongLyrics(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error getting song verses: %v", err)
+		return result, err
+	}
 
-	s.loger.Debugf("len couplets: %v", len(couplets))
-	s.loger.Debugf("couplets: %v", couplets)
+	loger.Debugf("len couplets: %v", len(lyrics.Sections))
 
-	if couplet > len(couplets) {
-		s.loger.Errorf("Error getting song verses: coupletId is out of range")
+	couplets, err := selectCouplets(lyrics.Sections, ranges)
+	if err != nil {
+		loger.Errorf("Error getting song verses: %v", err)
 		return result, err
 	}
 
+	texts := make([]string, 0, len(couplets))
+	for _, couplet := range couplets {
+		texts = append(texts, couplet.Text)
+	}
+
 	result = models.SongVerseResponse{
-		ID:        guid,
-		CoupletId: couplet,
-		Couplet:   couplets[couplet],
+		ID:            guid,
+		CoupletId:     couplets[0].Number,
+		Couplet:       strings.Join(texts, "\n\n"),
+		Couplets:      couplets,
+		TotalCouplets: len(lyrics.Sections),
+	}
+
+	return result, nil
+}
+
+// GetSongLyrics : Получение разобранного текста песни. Для песен, записанных до появления разбора,
+// текст разбирается при чтении
+func (s Service) GetSongLyrics(ctx context.Context, guid string) (models.SongLyricsResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetSongLyrics")
+	defer span.End()
+	loger.Debugln("Getting song lyrics in service")
+	result := models.SongLyricsResponse{ID: guid}
+
+	text, sections, err := s.store.GetSongLyrics(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error getting song lyrics: %v", err)
+		return result, err
+	}
+
+	if sections == nil {
+		sections = ParseLyrics(text)
 	}
+	result.Sections = sections
 
 	return result, nil
 }
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
+)
+
+// GetTrash : Получение списка удаленных песен и вызов сервиса хранилища
+func (s Service) GetTrash(ctx context.Context, paginationOptions models.PaginationOptions) (models.TrashResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.GetTrash")
+	defer span.End()
+	loger.Debugln("Getting trash in service")
+	result := models.TrashResponse{}
+
+	_, err := strconv.Atoi(paginationOptions.Limit)
+	if err != nil {
+		loger.Errorf("Error converting limit to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Limit must be a number", err)
+	}
+	_, err = strconv.Atoi(paginationOptions.Offset)
+	if err != nil {
+		loger.Errorf("Error converting offset to int: %v", err)
+		return result, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Offset must be a number", err)
+	}
+
+	result, err = s.store.GetTrash(ctx, paginationOptions)
+	if err != nil {
+		loger.Errorf("Error getting trash: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// RestoreSong : Восстановление песни из корзины и вызов сервиса хранилища
+func (s Service) RestoreSong(ctx context.Context, guid string) (models.Song, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.RestoreSong")
+	defer span.End()
+	loger.Debugln("Restoring song in service")
+	result := models.Song{}
+
+	result, err := s.store.RestoreSong(ctx, guid)
+	if err != nil {
+		loger.Errorf("Error restoring song: %v", err)
+		return result, err
+	}
+
+	return result, nil
+}
+
+// PurgeTrash : Окончательное удаление песен, пролежавших в корзине дольше retention
+func (s Service) PurgeTrash(ctx context.Context, retention time.Duration) (int64, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, span := tracing.Start(ctx, "Service.PurgeTrash")
+	defer span.End()
+	loger.Debugln("Purging trash in service")
+
+	purged, err := s.store.PurgeSongs(ctx, time.Now().UTC().Add(-retention))
+	if err != nil {
+		loger.Errorf("Error purging trash: %v", err)
+		return 0, err
+	}
+
+	return purged, nil
+}
+
+// RunTrashPurge : Периодическая очистка корзины раз в interval до отмены ctx
+func (s Service) RunTrashPurge(ctx context.Context, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purged, err := s.PurgeTrash(ctx, retention)
+		if err != nil {
+			s.loger.Errorf("Trash purge failed: %v", err)
+		} else if purged > 0 {
+			s.loger.Infof("Songs purged from trash: %v", purged)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
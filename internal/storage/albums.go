@@ -0,0 +1,295 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// selectAlbums : Выборка альбомов с названием исполнителя
+func selectAlbums() string {
+	return "SELECT albums.id, albums.title, albums.artist_id, artists.name AS artist_name, albums.release_date, albums.cover_link " +
+		"FROM public.albums JOIN public.artists ON artists.id = albums.artist_id"
+}
+
+// CreateAlbum : Создание альбома с треками из песен tracks в базе данных
+func (s Storage) CreateAlbum(ctx context.Context, album models.Album, tracks []string) (string, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "CreateAlbum")
+	defer cancel()
+
+	loger.Debugln("Creating album in the database")
+	result := models.AlbumResponse{}
+
+	albumDB := albumToDB(album)
+
+	err := pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(ctx, "INSERT INTO public.albums (title, artist_id, release_date, cover_link) VALUES ($1, $2::uuid, $3, $4) RETURNING id",
+			albumDB.Title, albumDB.ArtistID, albumDB.Release, albumDB.CoverLink).Scan(&result.ID)
+		if err != nil {
+			return err
+		}
+		return setAlbumTracks(ctx, tx, result.ID, tracks)
+	})
+	if err != nil {
+		loger.Errorf("Error creating album in the database: %v", err.Error())
+		return "", dbError(err, "album")
+	}
+
+	loger.Debugln("Album created in the database")
+	return result.ID, nil
+}
+
+// ReadAlbum : Получение альбома по его ID с треками из базы данных. Песни из корзины в треки не попадают,
+// позиции остальных треков идут подряд
+func (s Storage) ReadAlbum(ctx context.Context, guid string) (result models.Album, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "ReadAlbum")
+	defer cancel()
+
+	loger.Debugln("Reading album from the database")
+
+	rows, err := s.db.Query(ctx, selectAlbums()+" WHERE albums.id = $1::uuid", guid)
+	if err != nil {
+		loger.Errorf("Error getting album from the database: %v", err.Error())
+		return result, dbError(err, "album")
+	}
+
+	albumDB, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.AlbumDB])
+	if err != nil {
+		loger.Errorf("Error getting album from the database: %v", err.Error())
+		return result, dbError(err, "album")
+	}
+
+	rows, err = s.db.Query(ctx, `SELECT row_number() OVER (ORDER BY album_tracks.position) AS position,
+			songs.id, songs.song_name, songs.artist_name, songs.release_date, songs.song_text, songs.link, songs.version
+		FROM public.album_tracks JOIN public.songs ON songs.id = album_tracks.song_id
+		WHERE album_tracks.album_id = $1::uuid AND songs.deleted_at IS NULL
+		ORDER BY album_tracks.position`, guid)
+	if err != nil {
+		loger.Errorf("Error getting album tracks from the database: %v", err.Error())
+		return result, dbError(err, "album")
+	}
+
+	tracksDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AlbumTrackDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "album")
+	}
+
+	result = albumFromDB(albumDB)
+	result.Tracks = make([]models.AlbumTrack, 0, len(tracksDB))
+	for _, trackDB := range tracksDB {
+		result.Tracks = append(result.Tracks, models.AlbumTrack{
+			Position: trackDB.Position,
+			Song:     songFromDB(trackDB.SongDB),
+		})
+	}
+
+	loger.Debugln("Album read from the database")
+	return result, nil
+}
+
+// GetAlbumsList : Получение списка альбомов без треков из базы данных в порядке названий
+func (s Storage) GetAlbumsList(ctx context.Context, paginationOptions models.PaginationOptions) (result models.AlbumsListResponse, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetAlbumsList")
+	defer cancel()
+
+	loger.Debugln("Reading albums list from the database")
+
+	limit, _ := strconv.Atoi(paginationOptions.Limit)
+	offset, _ := strconv.Atoi(paginationOptions.Offset)
+
+	rows, err := s.db.Query(ctx, selectAlbums()+" ORDER BY lower(albums.title), albums.id LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		loger.Errorf("Error getting albums list from the database: %v", err.Error())
+		return result, dbError(err, "album")
+	}
+
+	albumsDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.AlbumDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "album")
+	}
+
+	result.Albums = make([]models.Album, 0, len(albumsDB))
+	for _, albumDB := range albumsDB {
+		result.Albums = append(result.Albums, albumFromDB(albumDB))
+	}
+
+	loger.Debugln("Albums list read from the database")
+	return result, nil
+}
+
+// UpdateAlbum : Обновление альбома в базе данных. Если tracks не nil, треки альбома заменяются
+func (s Storage) UpdateAlbum(ctx context.Context, album models.Album, tracks []string) (models.Album, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	queryCtx, cancel := s.queryContext(ctx, "UpdateAlbum")
+	defer cancel()
+
+	loger.Debugln("Updating album in the database")
+
+	albumDB := albumToDB(album)
+
+	err := pgx.BeginFunc(queryCtx, s.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(queryCtx, "UPDATE public.albums SET title = $2, artist_id = $3::uuid, release_date = $4, cover_link = $5 WHERE id = $1::uuid RETURNING id",
+			albumDB.ID, albumDB.Title, albumDB.ArtistID, albumDB.Release, albumDB.CoverLink).Scan(&albumDB.ID)
+		if err != nil || tracks == nil {
+			return err
+		}
+		return setAlbumTracks(queryCtx, tx, albumDB.ID, tracks)
+	})
+	if err != nil {
+		loger.Errorf("Error updating album in the database: %v", err.Error())
+		return album, dbError(err, "album")
+	}
+
+	loger.Debugln("Album updated in the database")
+	return s.ReadAlbum(ctx, albumDB.ID)
+}
+
+// DeleteAlbum : Удаление альбома вместе с треками из базы данных, песни не удаляются
+func (s Storage) DeleteAlbum(ctx context.Context, guid string) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "DeleteAlbum")
+	defer cancel()
+
+	loger.Debugln("Deleting album in the database")
+
+	err := s.db.QueryRow(ctx, "DELETE FROM public.albums WHERE id = $1::uuid RETURNING id", guid).Scan(&guid)
+	if err != nil {
+		loger.Errorf("Error deleting album in the database: %v", err.Error())
+		return dbError(err, "album")
+	}
+
+	loger.Debugln("Album deleted in the database")
+	return nil
+}
+
+// SetAlbumTracks : Замена треков альбома песнями tracks в заданном порядке
+func (s Storage) SetAlbumTracks(ctx context.Context, guid string, tracks []string) (models.Album, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	queryCtx, cancel := s.queryContext(ctx, "SetAlbumTracks")
+	defer cancel()
+
+	loger.Debugln("Setting album tracks in the database")
+
+	err := pgx.BeginFunc(queryCtx, s.db, func(tx pgx.Tx) error {
+		if err := lockAlbum(queryCtx, tx, guid); err != nil {
+			return err
+		}
+		return setAlbumTracks(queryCtx, tx, guid, tracks)
+	})
+	if err != nil {
+		loger.Errorf("Error setting album tracks in the database: %v", err.Error())
+		return models.Album{}, dbError(err, "album")
+	}
+
+	loger.Debugln("Album tracks set in the database")
+	return s.ReadAlbum(ctx, guid)
+}
+
+// AddAlbumTrack : Добавление песни последним треком альбома
+func (s Storage) AddAlbumTrack(ctx context.Context, guid string, songID string) (models.Album, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	queryCtx, cancel := s.queryContext(ctx, "AddAlbumTrack")
+	defer cancel()
+
+	loger.Debugln("Adding album track in the database")
+
+	err := pgx.BeginFunc(queryCtx, s.db, func(tx pgx.Tx) error {
+		if err := lockAlbum(queryCtx, tx, guid); err != nil {
+			return dbError(err, "album")
+		}
+		if err := checkSongs(queryCtx, tx, []string{songID}); err != nil {
+			return err
+		}
+		_, err := tx.Exec(queryCtx, `INSERT INTO public.album_tracks (album_id, song_id, position)
+			SELECT $1::uuid, $2::uuid, coalesce(max(position), 0) + 1 FROM public.album_tracks WHERE album_id = $1::uuid`,
+			guid, songID)
+		return dbError(err, "track")
+	})
+	if err != nil {
+		loger.Errorf("Error adding album track in the database: %v", err.Error())
+		return models.Album{}, dbError(err, "album")
+	}
+
+	loger.Debugln("Album track added in the database")
+	return s.ReadAlbum(ctx, guid)
+}
+
+// lockAlbum : Блокировка альбома до конца транзакции, чтобы треки не менялись параллельно
+func lockAlbum(ctx context.Context, tx pgx.Tx, guid string) error {
+	return tx.QueryRow(ctx, "SELECT id FROM public.albums WHERE id = $1::uuid FOR UPDATE", guid).Scan(&guid)
+}
+
+// setAlbumTracks : Замена треков альбома, позиции треков нумеруются с 1 в порядке tracks
+func setAlbumTracks(ctx context.Context, tx pgx.Tx, albumID string, tracks []string) error {
+	if _, err := tx.Exec(ctx, "DELETE FROM public.album_tracks WHERE album_id = $1::uuid", albumID); err != nil {
+		return err
+	}
+	if len(tracks) == 0 {
+		return nil
+	}
+	if err := checkSongs(ctx, tx, tracks); err != nil {
+		return err
+	}
+
+	_, err := tx.Exec(ctx, `INSERT INTO public.album_tracks (album_id, song_id, position)
+		SELECT $1::uuid, tracks.song_id, tracks.position FROM unnest($2::uuid[]) WITH ORDINALITY AS tracks (song_id, position)`,
+		albumID, tracks)
+	return err
+}
+
+// checkSongs : Проверка, что все песни существуют и не удалены. ID песен не повторяются
+func checkSongs(ctx context.Context, tx pgx.Tx, songs []string) error {
+	var found int
+	err := tx.QueryRow(ctx, "SELECT count(*) FROM public.songs WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL", songs).Scan(&found)
+	if err != nil {
+		return dbError(err, "song")
+	}
+	if found != len(songs) {
+		return models.NewError(models.ErrValidation, models.CodeSongNotFound, "Songs must exist and must not be in the trash", nil)
+	}
+	return nil
+}
+
+// albumToDB : Преобразование альбома в формат базы данных, пустые поля становятся NULL
+func albumToDB(album models.Album) models.AlbumDB {
+	var releaseDate time.Time
+	if album.Release != "" {
+		releaseDate, _ = time.Parse("02.01.2006", album.Release)
+	}
+
+	return models.AlbumDB{
+		ID:        album.ID,
+		Title:     album.Title,
+		ArtistID:  album.ArtistID,
+		Release:   sql.NullTime{Time: releaseDate, Valid: album.Release != ""},
+		CoverLink: sql.NullString{String: album.CoverLink, Valid: album.CoverLink != ""},
+	}
+}
+
+// albumFromDB : Преобразование альбома из формата базы данных, NULL поля становятся пустыми строками
+func albumFromDB(albumDB models.AlbumDB) models.Album {
+	var rlsDate string
+	if albumDB.Release.Valid {
+		rlsDate = albumDB.Release.Time.Format("02.01.2006")
+	}
+
+	return models.Album{
+		ID:        albumDB.ID,
+		Title:     albumDB.Title,
+		ArtistID:  albumDB.ArtistID,
+		Artist:    albumDB.Artist,
+		Release:   rlsDate,
+		CoverLink: albumDB.CoverLink.String,
+	}
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// apiKeyColumns : колонки таблицы api_keys без хэша ключа
+const apiKeyColumns = "id, name, prefix, scopes, expires_at, created_by, created_at, last_used_at, revoked_at"
+
+// apiKeyTouchInterval : как часто обновляется время использования ключа, чтобы запросы с ключом не писали в базу каждый раз
+const apiKeyTouchInterval = "1 minute"
+
+// CreateAPIKey : Сохранение нового ключа API с хэшем hash в базе данных
+func (s Storage) CreateAPIKey(ctx context.Context, key models.APIKey, hash string) (models.APIKey, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "CreateAPIKey")
+	defer cancel()
+
+	loger.Debugln("Creating API key in the database")
+
+	keyDB := apiKeyToDB(key)
+
+	rows, err := s.db.Query(ctx, "INSERT INTO public.api_keys (name, prefix, key_hash, scopes, expires_at, created_by) VALUES ($1, $2, $3, $4, $5, $6) RETURNING "+apiKeyColumns,
+		keyDB.Name, keyDB.Prefix, hash, keyDB.Scopes, keyDB.ExpiresAt, keyDB.CreatedBy)
+	if err != nil {
+		loger.Errorf("Error creating API key in the database: %v", err.Error())
+		return key, dbError(err, "key")
+	}
+
+	keyDB, err = pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.APIKeyDB])
+	if err != nil {
+		loger.Errorf("Error creating API key in the database: %v", err.Error())
+		return key, dbError(err, "key")
+	}
+
+	loger.Debugln("API key created in the database")
+	return apiKeyFromDB(keyDB), nil
+}
+
+// GetAPIKeysList : Получение списка ключей API из базы данных, последние выпущенные первыми
+func (s Storage) GetAPIKeysList(ctx context.Context, paginationOptions models.PaginationOptions) (result models.APIKeysListResponse, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetAPIKeysList")
+	defer cancel()
+
+	loger.Debugln("Reading API keys list from the database")
+
+	limit, _ := strconv.Atoi(paginationOptions.Limit)
+	offset, _ := strconv.Atoi(paginationOptions.Offset)
+
+	rows, err := s.db.Query(ctx, "SELECT "+apiKeyColumns+" FROM public.api_keys ORDER BY created_at DESC, id LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		loger.Errorf("Error getting API keys list from the database: %v", err.Error())
+		return result, dbError(err, "key")
+	}
+
+	keysDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.APIKeyDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "key")
+	}
+
+	result.Keys = make([]models.APIKey, 0, len(keysDB))
+	for _, keyDB := range keysDB {
+		result.Keys = append(result.Keys, apiKeyFromDB(keyDB))
+	}
+
+	loger.Debugln("API keys list read from the database")
+	return result, nil
+}
+
+// RotateAPIKey : Замена ключа API на ключ с хэшем hash. Прежний ключ сразу перестает действовать,
+// отозванный ключ не ротируется
+func (s Storage) RotateAPIKey(ctx context.Context, guid string, prefix string, hash string) (models.APIKey, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "RotateAPIKey")
+	defer cancel()
+
+	loger.Debugln("Rotating API key in the database")
+	result := models.APIKey{}
+
+	rows, err := s.db.Query(ctx, "UPDATE public.api_keys SET prefix = $2, key_hash = $3, last_used_at = NULL WHERE id = $1::uuid AND revoked_at IS NULL RETURNING "+apiKeyColumns,
+		guid, prefix, hash)
+	if err != nil {
+		loger.Errorf("Error rotating API key in the database: %v", err.Error())
+		return result, dbError(err, "key")
+	}
+
+	keyDB, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.APIKeyDB])
+	if err != nil {
+		loger.Errorf("Error rotating API key in the database: %v", err.Error())
+		return result, dbError(err, "key")
+	}
+
+	loger.Debugln("API key rotated in the database")
+	return apiKeyFromDB(keyDB), nil
+}
+
+// RevokeAPIKey : Отзыв ключа API в базе данных. Ключ остается в списке со временем отзыва
+func (s Storage) RevokeAPIKey(ctx context.Context, guid string) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "RevokeAPIKey")
+	defer cancel()
+
+	loger.Debugln("Revoking API key in the database")
+
+	err := s.db.QueryRow(ctx, "UPDATE public.api_keys SET revoked_at = now() at time zone 'utc' WHERE id = $1::uuid AND revoked_at IS NULL RETURNING id", guid).Scan(&guid)
+	if err != nil {
+		loger.Errorf("Error revoking API key in the database: %v", err.Error())
+		return dbError(err, "key")
+	}
+
+	loger.Debugln("API key revoked in the database")
+	return nil
+}
+
+// UseAPIKey : Поиск действующего ключа API по хэшу. Отозванный и просроченный ключ не находится.
+// Время использования обновляется не чаще apiKeyTouchInterval, ошибка обновления не отклоняет ключ
+func (s Storage) UseAPIKey(ctx context.Context, hash string) (models.APIKey, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "UseAPIKey")
+	defer cancel()
+
+	loger.Debugln("Using API key in the database")
+	result := models.APIKey{}
+
+	rows, err := s.db.Query(ctx, `SELECT `+apiKeyColumns+` FROM public.api_keys
+		WHERE key_hash = $1 AND revoked_at IS NULL AND (expires_at IS NULL OR expires_at > now() at time zone 'utc')`, hash)
+	if err != nil {
+		loger.Errorf("Error using API key in the database: %v", err.Error())
+		return result, dbError(err, "key")
+	}
+
+	keyDB, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.APIKeyDB])
+	if err != nil {
+		return result, dbError(err, "key")
+	}
+
+	_, err = s.db.Exec(ctx, `UPDATE public.api_keys SET last_used_at = now() at time zone 'utc'
+		WHERE id = $1 AND (last_used_at IS NULL OR last_used_at < now() at time zone 'utc' - interval '`+apiKeyTouchInterval+`')`, keyDB.ID)
+	if err != nil {
+		loger.Warnf("Error updating API key last use in the database: %v", err.Error())
+	}
+
+	return apiKeyFromDB(keyDB), nil
+}
+
+// apiKeyToDB : Преобразование ключа API в формат базы данных, время хранится в UTC
+func apiKeyToDB(key models.APIKey) models.APIKeyDB {
+	keyDB := models.APIKeyDB{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scopes:    key.Scopes,
+		CreatedBy: sql.NullString{String: key.CreatedBy, Valid: key.CreatedBy != ""},
+	}
+	if key.ExpiresAt != nil {
+		keyDB.ExpiresAt = sql.NullTime{Time: key.ExpiresAt.UTC(), Valid: true}
+	}
+	return keyDB
+}
+
+// apiKeyFromDB : Преобразование ключа API из формата базы данных, NULL время становится nil
+func apiKeyFromDB(keyDB models.APIKeyDB) models.APIKey {
+	nullTime := func(value sql.NullTime) *time.Time {
+		if !value.Valid {
+			return nil
+		}
+		return &value.Time
+	}
+
+	return models.APIKey{
+		ID:         keyDB.ID,
+		Name:       keyDB.Name,
+		Prefix:     keyDB.Prefix,
+		Scopes:     keyDB.Scopes,
+		ExpiresAt:  nullTime(keyDB.ExpiresAt),
+		CreatedBy:  keyDB.CreatedBy.String,
+		CreatedAt:  keyDB.CreatedAt,
+		LastUsedAt: nullTime(keyDB.LastUsedAt),
+		RevokedAt:  nullTime(keyDB.RevokedAt),
+	}
+}
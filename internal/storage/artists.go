@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// selectArtists : Выборка исполнителей с числом их неудаленных песен
+func selectArtists() sq.SelectBuilder {
+	return psql.Select("artists.id", "artists.name", "count(songs.id) AS songs_count").
+		From("public.artists").
+		LeftJoin("public.songs ON songs.artist_id = artists.id AND songs.deleted_at IS NULL").
+		GroupBy("artists.id")
+}
+
+// CreateArtist : Создание исполнителя в базе данных. Названия, отличающиеся только регистром, совпадают
+func (s Storage) CreateArtist(ctx context.Context, name string) (result models.Artist, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "CreateArtist")
+	defer cancel()
+
+	loger.Debugln("Creating artist in the database")
+
+	err = s.db.QueryRow(ctx, "INSERT INTO public.artists (name) VALUES (btrim($1)) RETURNING id, name", name).
+		Scan(&result.ID, &result.Name)
+	if err != nil {
+		loger.Errorf("Error creating artist in the database: %v", err.Error())
+		return result, dbError(err, "artist")
+	}
+
+	loger.Debugln("Artist created in the database")
+	return result, nil
+}
+
+// ReadArtist : Получение исполнителя по его ID из базы данных
+func (s Storage) ReadArtist(ctx context.Context, guid string) (result models.Artist, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "ReadArtist")
+	defer cancel()
+
+	loger.Debugln("Reading artist from the database")
+
+	query, args, err := selectArtists().Where("artists.id = ?::uuid", guid).ToSql()
+	if err != nil {
+		loger.Errorf("Error building query: %v", err)
+		return result, err
+	}
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		loger.Errorf("Error getting artist from the database: %v", err.Error())
+		return result, dbError(err, "artist")
+	}
+
+	artistDB, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.ArtistDB])
+	if err != nil {
+		loger.Errorf("Error getting artist from the database: %v", err.Error())
+		return result, dbError(err, "artist")
+	}
+
+	loger.Debugln("Artist read from the database")
+	return artistFromDB(artistDB), nil
+}
+
+// GetArtistsList : Получение списка исполнителей из базы данных в порядке названий
+func (s Storage) GetArtistsList(ctx context.Context, paginationOptions models.PaginationOptions) (result models.ArtistsListResponse, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetArtistsList")
+	defer cancel()
+
+	loger.Debugln("Reading artists list from the database")
+
+	limit, _ := strconv.Atoi(paginationOptions.Limit)
+	offset, _ := strconv.Atoi(paginationOptions.Offset)
+
+	query, args, err := selectArtists().
+		OrderBy("lower(artists.name)", "artists.id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		loger.Errorf("Error building query: %v", err)
+		return result, err
+	}
+	loger.Debugf("Query: %v", query)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		loger.Errorf("Error getting artists list from the database: %v", err.Error())
+		return result, dbError(err, "artist")
+	}
+
+	artistsDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.ArtistDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "artist")
+	}
+
+	result.Artists = make([]models.Artist, 0, len(artistsDB))
+	for _, artistDB := range artistsDB {
+		result.Artists = append(result.Artists, artistFromDB(artistDB))
+	}
+
+	loger.Debugln("Artists list read from the database")
+	return result, nil
+}
+
+// UpdateArtist : Переименование исполнителя в базе данных. Новое название сразу видно во всех его песнях,
+// версии песен увеличиваются, а изменения записываются в журнал ревизий
+func (s Storage) UpdateArtist(ctx context.Context, artist models.Artist) (models.Artist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	queryCtx, cancel := s.queryContext(ctx, "UpdateArtist")
+	defer cancel()
+
+	loger.Debugln("Updating artist in the database")
+
+	err := s.withAudit(queryCtx, func(tx pgx.Tx) error {
+		return tx.QueryRow(queryCtx, "UPDATE public.artists SET name = btrim($2) WHERE id = $1::uuid RETURNING id",
+			artist.ID, artist.Name).Scan(&artist.ID)
+	})
+	if err != nil {
+		loger.Errorf("Error updating artist in the database: %v", err.Error())
+		return artist, dbError(err, "artist")
+	}
+
+	loger.Debugln("Artist updated in the database")
+	return s.ReadArtist(ctx, artist.ID)
+}
+
+// DeleteArtist : Удаление исполнителя из базы данных. Исполнителя, у которого есть песни,
+// в том числе в корзине, удалить нельзя
+func (s Storage) DeleteArtist(ctx context.Context, guid string) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "DeleteArtist")
+	defer cancel()
+
+	loger.Debugln("Deleting artist in the database")
+
+	err := s.db.QueryRow(ctx, "DELETE FROM public.artists WHERE id = $1::uuid RETURNING id", guid).Scan(&guid)
+	if err != nil {
+		loger.Errorf("Error deleting artist in the database: %v", err.Error())
+		return dbError(err, "artist")
+	}
+
+	loger.Debugln("Artist deleted in the database")
+	return nil
+}
+
+// artistFromDB : Преобразование исполнителя из формата базы данных
+func artistFromDB(artistDB models.ArtistDB) models.Artist {
+	return models.Artist{
+		ID:         artistDB.ID,
+		Name:       artistDB.Name,
+		SongsCount: artistDB.SongsCount,
+	}
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/cache"
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// songInfoKey : ключ кэша информации о песне, исполнитель сравнивается без учета регистра, как в запросе
+type songInfoKey struct {
+	name   string
+	artist string
+}
+
+type songLyrics struct {
+	text     string
+	sections []models.LyricsSection
+}
+
+// CachedStorage : кэш чтения песен поверх хранилища. Остальные методы передаются хранилищу без изменений.
+// Любое изменение песен удаляет затронутые записи после записи в базу данных, поэтому чтение
+// в этой реплике сразу видит изменения. Изменения из других реплик видны не позже чем через TTL
+type CachedStorage struct {
+	SongStorage
+	songs  *cache.LRU[string, models.Song]
+	info   *cache.LRU[songInfoKey, models.SongInfoResponse]
+	lyrics *cache.LRU[string, songLyrics]
+	loger  *zap.SugaredLogger
+}
+
+// NewCachedStorage : config.Size ограничивает число записей каждого из кэшей. Загрузка промаха
+// не прерывается отменой запроса, который ее начал, поэтому ограничена config.LoadTimeout
+func NewCachedStorage(store SongStorage, config cache.Config, loger *zap.SugaredLogger) *CachedStorage {
+	return &CachedStorage{
+		SongStorage: store,
+		songs:       cache.New[string, models.Song](config),
+		info:        cache.New[songInfoKey, models.SongInfoResponse](config),
+		lyrics:      cache.New[string, songLyrics](config),
+		loger:       loger,
+	}
+}
+
+// ReadSong : Песня из кэша или из хранилища
+func (s CachedStorage) ReadSong(ctx context.Context, guid string) (models.Song, error) {
+	return s.songs.Load(ctx, guid, func(ctx context.Context) (models.Song, error) {
+		return s.SongStorage.ReadSong(ctx, guid)
+	})
+}
+
+// GetSongInfo : Информация о песне из кэша или из хранилища
+func (s CachedStorage) GetSongInfo(ctx context.Context, song models.SongRequest) (models.SongInfoResponse, error) {
+	key := songInfoKey{name: song.Name, artist: strings.ToLower(strings.TrimSpace(song.Artist))}
+	return s.info.Load(ctx, key, func(ctx context.Context) (models.SongInfoResponse, error) {
+		return s.SongStorage.GetSongInfo(ctx, song)
+	})
+}
+
+// GetSongLyrics : Текст и куплеты песни из кэша или из хранилища
+func (s CachedStorage) GetSongLyrics(ctx context.Context, guid string) (string, []models.LyricsSection, error) {
+	lyrics, err := s.lyrics.Load(ctx, guid, func(ctx context.Context) (songLyrics, error) {
+		text, sections, err := s.SongStorage.GetSongLyrics(ctx, guid)
+		return songLyrics{text: text, sections: sections}, err
+	})
+	return lyrics.text, lyrics.sections, err
+}
+
+// invalidateSong : Удаление песни из кэша. Информация о песне хранится по названию и исполнителю,
+// прежние значения которых неизвестны, поэтому она удаляется целиком
+func (s CachedStorage) invalidateSong(ctx context.Context, guid string) {
+	reqctx.Logger(ctx, s.loger).Debugf("Invalidating song %v in cache", guid)
+	s.songs.Delete(guid)
+	s.lyrics.Delete(guid)
+	s.info.Purge()
+}
+
+// invalidateAll : Удаление всех песен из кэша после массовых изменений
+func (s CachedStorage) invalidateAll(ctx context.Context) {
+	reqctx.Logger(ctx, s.loger).Debugln("Invalidating all songs in cache")
+	s.songs.Purge()
+	s.lyrics.Purge()
+	s.info.Purge()
+}
+
+func (s CachedStorage) CreateSong(ctx context.Context, song models.Song) (string, error) {
+	guid, err := s.SongStorage.CreateSong(ctx, song)
+	s.info.Purge()
+	return guid, err
+}
+
+func (s CachedStorage) UpdateSong(ctx context.Context, song models.Song, ifMatch []int) (models.Song, error) {
+	result, err := s.SongStorage.UpdateSong(ctx, song, ifMatch)
+	s.invalidateSong(ctx, song.ID)
+	return result, err
+}
+
+func (s CachedStorage) PatchSong(ctx context.Context, guid string, patch models.SongPatch, ifMatch []int) (models.Song, error) {
+	result, err := s.SongStorage.PatchSong(ctx, guid, patch, ifMatch)
+	s.invalidateSong(ctx, guid)
+	return result, err
+}
+
+func (s CachedStorage) DeleteSong(ctx context.Context, guid string, ifMatch []int) (models.SongResponse, error) {
+	result, err := s.SongStorage.DeleteSong(ctx, guid, ifMatch)
+	s.invalidateSong(ctx, guid)
+	return result, err
+}
+
+func (s CachedStorage) RestoreSong(ctx context.Context, guid string) (models.Song, error) {
+	result, err := s.SongStorage.RestoreSong(ctx, guid)
+	s.invalidateSong(ctx, guid)
+	return result, err
+}
+
+func (s CachedStorage) PurgeSongs(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	result, err := s.SongStorage.PurgeSongs(ctx, deletedBefore)
+	s.invalidateAll(ctx)
+	return result, err
+}
+
+func (s CachedStorage) ImportSongs(ctx context.Context, songs []models.Song, dryRun bool) ([]models.Song, error) {
+	result, err := s.SongStorage.ImportSongs(ctx, songs, dryRun)
+	if !dryRun {
+		s.invalidateAll(ctx)
+	}
+	return result, err
+}
+
+// UpdateArtist : Переименование исполнителя меняет все его песни
+func (s CachedStorage) UpdateArtist(ctx context.Context, artist models.Artist) (models.Artist, error) {
+	result, err := s.SongStorage.UpdateArtist(ctx, artist)
+	s.invalidateAll(ctx)
+	return result, err
+}
+
+// CacheStats : Счетчики кэшей песен, информации о песнях и текстов
+func (s CachedStorage) CacheStats() []models.CacheStats {
+	result := make([]models.CacheStats, 0, 3)
+	for _, c := range []struct {
+		name  string
+		stats cache.Stats
+	}{
+		{"songs", s.songs.Stats()},
+		{"songInfo", s.info.Stats()},
+		{"lyrics", s.lyrics.Stats()},
+	} {
+		stats := models.CacheStats{
+			Name:      c.name,
+			Hits:      c.stats.Hits,
+			Misses:    c.stats.Misses,
+			Evictions: c.stats.Evictions,
+			Size:      c.stats.Size,
+			Capacity:  c.stats.Capacity,
+		}
+		if total := stats.Hits + stats.Misses; total > 0 {
+			stats.HitRatio = float64(stats.Hits) / float64(total)
+		}
+		result = append(result, stats)
+	}
+	return result
+}
@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"EffectiveMobile/internal/cache"
+	"EffectiveMobile/internal/models"
+)
+
+// countingStorage : хранилище в памяти, которое считает чтения
+type countingStorage struct {
+	SongStorage
+	songs map[string]models.Song
+	reads int
+}
+
+func (s *countingStorage) ReadSong(_ context.Context, guid string) (models.Song, error) {
+	s.reads++
+	song, ok := s.songs[guid]
+	if !ok {
+		return song, models.NewError(models.ErrNotFound, models.CodeSongNotFound, "Song not found", nil)
+	}
+	return song, nil
+}
+
+func (s *countingStorage) UpdateSong(_ context.Context, song models.Song, _ []int) (models.Song, error) {
+	s.songs[song.ID] = song
+	return song, nil
+}
+
+func (s *countingStorage) GetSongInfo(_ context.Context, song models.SongRequest) (models.SongInfoResponse, error) {
+	s.reads++
+	return models.SongInfoResponse{Text: song.Name}, nil
+}
+
+func TestCachedStorage(t *testing.T) {
+	ctx := context.Background()
+	next := &countingStorage{songs: map[string]models.Song{"1": {ID: "1", Name: "Hysteria"}}}
+	store := NewCachedStorage(next, cache.Config{Size: 10}, zap.NewNop().Sugar())
+
+	for i := 0; i < 3; i++ {
+		song, err := store.ReadSong(ctx, "1")
+		require.NoError(t, err)
+		assert.Equal(t, "Hysteria", song.Name)
+	}
+	assert.Equal(t, 1, next.reads)
+
+	// Изменение сразу видно при чтении
+	_, err := store.UpdateSong(ctx, models.Song{ID: "1", Name: "Uprising"}, nil)
+	require.NoError(t, err)
+	song, err := store.ReadSong(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, "Uprising", song.Name)
+	assert.Equal(t, 2, next.reads)
+
+	// Отсутствующая песня не кэшируется
+	_, err = store.ReadSong(ctx, "2")
+	assert.ErrorIs(t, err, models.ErrNotFound)
+	next.songs["2"] = models.Song{ID: "2", Name: "Starlight"}
+	song, err = store.ReadSong(ctx, "2")
+	require.NoError(t, err)
+	assert.Equal(t, "Starlight", song.Name)
+
+	// Исполнитель сравнивается без учета регистра и пробелов
+	_, err = store.GetSongInfo(ctx, models.SongRequest{Name: "Hysteria", Artist: "Muse"})
+	require.NoError(t, err)
+	_, err = store.GetSongInfo(ctx, models.SongRequest{Name: "Hysteria", Artist: " muse "})
+	require.NoError(t, err)
+	assert.Equal(t, 5, next.reads)
+
+	stats := store.CacheStats()
+	require.Len(t, stats, 3)
+	assert.Equal(t, "songs", stats[0].Name)
+	assert.Equal(t, uint64(2), stats[0].Hits)
+	assert.Equal(t, uint64(4), stats[0].Misses)
+	assert.Equal(t, "songInfo", stats[1].Name)
+	assert.Equal(t, uint64(1), stats[1].Hits)
+}
@@ -0,0 +1,67 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+
+	"EffectiveMobile/internal/models"
+)
+
+// Коды ошибок PostgreSQL
+const (
+	pgUniqueViolation     = "23505"
+	pgForeignKeyViolation = "23503"
+	pgDataExceptionClass  = "22"
+)
+
+// dbError : Преобразование ошибки драйвера в доменную ошибку для сущности entity ("song", "artist", ...)
+func dbError(err error, entity string) error {
+	if err == nil {
+		return nil
+	}
+
+	title := strings.ToUpper(entity[:1]) + entity[1:]
+
+	var domainErr *models.DomainError
+	var pgErr *pgconn.PgError
+	switch {
+	case errors.As(err, &domainErr):
+		// Ошибка уже преобразована, например для другой сущности внутри транзакции
+		return err
+	case errors.Is(err, pgx.ErrNoRows):
+		return models.NewError(models.ErrNotFound, entity+"_not_found", title+" not found", err)
+	case errors.As(err, &pgErr) && pgErr.Code == pgUniqueViolation:
+		return models.NewError(models.ErrConflict, entity+"_already_exists", title+" already exists", err)
+	case errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation:
+		return models.NewError(models.ErrConflict, entity+"_in_use", title+" is referenced by other records", err)
+	case errors.As(err, &pgErr) && strings.HasPrefix(pgErr.Code, pgDataExceptionClass):
+		return models.NewError(models.ErrValidation, models.CodeInvalidValue, "Invalid parameter value", err)
+	}
+	return err
+}
+
+// versionMismatch : Ошибка условного изменения песни, текущая версия которой не указана в If-Match
+func versionMismatch() error {
+	return models.NewError(models.ErrPreconditionFailed, models.CodeVersionMismatch, "Song was modified by another request", nil)
+}
+
+// versionError : Причина, по которой условное изменение не затронуло ни одной строки:
+// песни нет или ее версия не совпала с ожидаемой
+func (s Storage) versionError(ctx context.Context, guid string, err error) error {
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return dbError(err, "song")
+	}
+
+	var exists bool
+	if existsErr := s.db.QueryRow(ctx, "SELECT EXISTS (SELECT 1 FROM public.songs WHERE id = $1::uuid AND deleted_at IS NULL)", guid).Scan(&exists); existsErr != nil {
+		return dbError(existsErr, "song")
+	}
+	if !exists {
+		return dbError(err, "song")
+	}
+	return versionMismatch()
+}
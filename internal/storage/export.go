@@ -0,0 +1,75 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/jackc/pgx/v5"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// ExportSongs : Потоковое чтение всех песен, подходящих под фильтры, в порядке сортировки.
+// Строки читаются из результата запроса по мере поступления и передаются в fn, не накапливаясь в памяти.
+// Выгрузка может идти дольше queryTimeout, поэтому ограничена только контекстом запроса
+func (s Storage) ExportSongs(ctx context.Context, sortOptions models.SortOptions, filterOptions map[string]string, fn func(song models.Song) error) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.timeoutContext(ctx, "ExportSongs", 0)
+	defer cancel()
+
+	loger.Debugln("Exporting songs from the database")
+
+	sb := psql.Select("id", "song_name", "artist_name", "release_date", "song_text", "link", "version").
+		From("public.songs").
+		Where(sq.Eq{"deleted_at": nil})
+
+	sb, err := applyFilters(sb, filterOptions)
+	if err != nil {
+		loger.Errorf("Error applying filters: %v", err)
+		return err
+	}
+
+	sortColumn := mapDB[sortOptions.Field]
+	if sortColumn == "id" {
+		sb = sb.OrderBy(fmt.Sprintf("id %v", sortOptions.Order))
+	} else {
+		sb = sb.OrderBy(fmt.Sprintf("%v %v NULLS LAST", sortColumn, sortOptions.Order), fmt.Sprintf("id %v", sortOptions.Order))
+	}
+
+	query, args, err := sb.ToSql()
+	if err != nil {
+		loger.Errorf("Error building query: %v", err)
+		return err
+	}
+	loger.Debugf("Query: %v", query)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		loger.Errorf("Error exporting songs from the database: %v", err.Error())
+		return dbError(err, "song")
+	}
+	defer rows.Close()
+
+	exported := 0
+	for rows.Next() {
+		songDB, err := pgx.RowToStructByName[models.SongDB](rows)
+		if err != nil {
+			loger.Errorf("Error scanning row: %v", err.Error())
+			return dbError(err, "song")
+		}
+		if err = fn(songFromDB(songDB)); err != nil {
+			loger.Errorf("Error writing exported song: %v", err)
+			return err
+		}
+		exported++
+	}
+	if err = rows.Err(); err != nil {
+		loger.Errorf("Error exporting songs from the database: %v", err.Error())
+		return dbError(err, "song")
+	}
+
+	loger.Debugf("Songs exported from the database: %v", exported)
+	return nil
+}
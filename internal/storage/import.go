@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// errDryRun : откат транзакции импорта в режиме проверки
+var errDryRun = errors.New("dry run")
+
+// ImportSongs : Пакетное создание песен через COPY во временную таблицу в одной транзакции.
+// Строки копируются частями по importConfig.BatchSize. Песни, конфликтующие с существующими
+// по id или по названию и исполнителю, пропускаются. Импорт может идти дольше queryTimeout,
+// поэтому ограничен importConfig.Timeout. Возвращает созданные песни, в режиме dryRun транзакция откатывается
+func (s Storage) ImportSongs(ctx context.Context, songs []models.Song, dryRun bool) (result []models.Song, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.timeoutContext(ctx, "ImportSongs", s.importConfig.Timeout)
+	defer cancel()
+
+	loger.Debugf("Importing %v songs to the database, dry run: %v", len(songs), dryRun)
+
+	rows := make([][]any, 0, len(songs))
+	for i, song := range songs {
+		songDB := songToDB(song)
+		var id any
+		if song.ID != "" {
+			id = song.ID
+		}
+		rows = append(rows, []any{i, id, songDB.Name, songDB.Artist, songDB.Release, songDB.Text, songDB.Link, lyricsToDB(song.Lyrics)})
+	}
+
+	err = s.withAudit(ctx, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, `CREATE TEMPORARY TABLE songs_import (
+			row_number integer,
+			id text,
+			song_name varchar(255),
+			artist_name varchar(255),
+			release_date date,
+			song_text text,
+			link varchar(255),
+			lyrics jsonb
+		) ON COMMIT DROP`)
+		if err != nil {
+			return err
+		}
+
+		var copied int64
+		for _, batch := range importBatches(rows, s.importConfig.BatchSize) {
+			n, err := tx.CopyFrom(ctx, pgx.Identifier{"songs_import"},
+				[]string{"row_number", "id", "song_name", "artist_name", "release_date", "song_text", "link", "lyrics"},
+				pgx.CopyFromRows(batch))
+			if err != nil {
+				return err
+			}
+			copied += n
+		}
+		loger.Debugf("Rows copied: %v", copied)
+
+		created, err := tx.Query(ctx, `INSERT INTO public.songs (id, song_name, artist_name, release_date, song_text, link, lyrics)
+			SELECT coalesce(id::uuid, gen_random_uuid()), song_name, artist_name, release_date, song_text, link, lyrics
+			FROM songs_import
+			ORDER BY row_number
+			ON CONFLICT DO NOTHING
+			RETURNING id, song_name, artist_name`)
+		if err != nil {
+			return err
+		}
+		result, err = pgx.CollectRows(created, func(row pgx.CollectableRow) (models.Song, error) {
+			var song models.Song
+			err := row.Scan(&song.ID, &song.Name, &song.Artist)
+			return song, err
+		})
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		loger.Errorf("Error importing songs to the database: %v", err.Error())
+		return nil, dbError(err, "song")
+	}
+
+	loger.Debugf("Songs imported to the database: %v", len(result))
+	return result, nil
+}
+
+// importBatches : Разбиение строк COPY на части не больше size строк, size <= 0 - одна часть
+func importBatches(rows [][]any, size int) [][][]any {
+	if size <= 0 || len(rows) <= size {
+		return [][][]any{rows}
+	}
+	batches := make([][][]any, 0, (len(rows)+size-1)/size)
+	for start := 0; start < len(rows); start += size {
+		batches = append(batches, rows[start:min(start+size, len(rows))])
+	}
+	return batches
+}
@@ -0,0 +1,15 @@
+package storage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportBatches(t *testing.T) {
+	rows := [][]any{{0}, {1}, {2}, {3}, {4}}
+
+	assert.Equal(t, [][][]any{rows}, importBatches(rows, 0))
+	assert.Equal(t, [][][]any{rows}, importBatches(rows, 5))
+	assert.Equal(t, [][][]any{{{0}, {1}}, {{2}, {3}}, {{4}}}, importBatches(rows, 2))
+}
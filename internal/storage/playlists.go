@@ -0,0 +1,297 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+
+	"github.com/jackc/pgx/v5"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// playlistColumns : колонки таблицы playlists
+const playlistColumns = "id, name, description, owner"
+
+// CreatePlaylist : Создание пустого плейлиста в базе данных
+func (s Storage) CreatePlaylist(ctx context.Context, playlist models.Playlist) (string, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "CreatePlaylist")
+	defer cancel()
+
+	loger.Debugln("Creating playlist in the database")
+	result := models.PlaylistResponse{}
+
+	playlistDB := playlistToDB(playlist)
+
+	err := s.db.QueryRow(ctx, "INSERT INTO public.playlists (name, description, owner) VALUES ($1, $2, $3) RETURNING id",
+		playlistDB.Name, playlistDB.Description, playlistDB.Owner).Scan(&result.ID)
+	if err != nil {
+		loger.Errorf("Error creating playlist in the database: %v", err.Error())
+		return "", dbError(err, "playlist")
+	}
+
+	loger.Debugln("Playlist created in the database")
+	return result.ID, nil
+}
+
+// ReadPlaylist : Получение плейлиста по его ID с записями из базы данных
+func (s Storage) ReadPlaylist(ctx context.Context, guid string) (result models.Playlist, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "ReadPlaylist")
+	defer cancel()
+
+	loger.Debugln("Reading playlist from the database")
+
+	rows, err := s.db.Query(ctx, "SELECT "+playlistColumns+" FROM public.playlists WHERE id = $1::uuid", guid)
+	if err != nil {
+		loger.Errorf("Error getting playlist from the database: %v", err.Error())
+		return result, dbError(err, "playlist")
+	}
+
+	playlistDB, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.PlaylistDB])
+	if err != nil {
+		loger.Errorf("Error getting playlist from the database: %v", err.Error())
+		return result, dbError(err, "playlist")
+	}
+
+	rows, err = s.db.Query(ctx, `SELECT playlist_entries.position,
+			songs.id, songs.song_name, songs.artist_name, songs.release_date, songs.song_text, songs.link, songs.version
+		FROM public.playlist_entries JOIN public.songs ON songs.id = playlist_entries.song_id
+		WHERE playlist_entries.playlist_id = $1::uuid AND songs.deleted_at IS NULL
+		ORDER BY playlist_entries.position`, guid)
+	if err != nil {
+		loger.Errorf("Error getting playlist entries from the database: %v", err.Error())
+		return result, dbError(err, "playlist")
+	}
+
+	entriesDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.PlaylistEntryDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "playlist")
+	}
+
+	result = playlistFromDB(playlistDB)
+	result.Entries = make([]models.PlaylistEntry, 0, len(entriesDB))
+	for _, entryDB := range entriesDB {
+		result.Entries = append(result.Entries, models.PlaylistEntry{
+			Position: entryDB.Position,
+			Song:     songFromDB(entryDB.SongDB),
+		})
+	}
+
+	loger.Debugln("Playlist read from the database")
+	return result, nil
+}
+
+// GetPlaylistsList : Получение списка плейлистов без записей из базы данных в порядке названий
+func (s Storage) GetPlaylistsList(ctx context.Context, paginationOptions models.PaginationOptions) (result models.PlaylistsListResponse, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetPlaylistsList")
+	defer cancel()
+
+	loger.Debugln("Reading playlists list from the database")
+
+	limit, _ := strconv.Atoi(paginationOptions.Limit)
+	offset, _ := strconv.Atoi(paginationOptions.Offset)
+
+	rows, err := s.db.Query(ctx, "SELECT "+playlistColumns+" FROM public.playlists ORDER BY lower(name), id LIMIT $1 OFFSET $2", limit, offset)
+	if err != nil {
+		loger.Errorf("Error getting playlists list from the database: %v", err.Error())
+		return result, dbError(err, "playlist")
+	}
+
+	playlistsDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.PlaylistDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "playlist")
+	}
+
+	result.Playlists = make([]models.Playlist, 0, len(playlistsDB))
+	for _, playlistDB := range playlistsDB {
+		result.Playlists = append(result.Playlists, playlistFromDB(playlistDB))
+	}
+
+	loger.Debugln("Playlists list read from the database")
+	return result, nil
+}
+
+// UpdatePlaylist : Изменение названия и описания плейлиста в базе данных
+func (s Storage) UpdatePlaylist(ctx context.Context, playlist models.Playlist) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	queryCtx, cancel := s.queryContext(ctx, "UpdatePlaylist")
+	defer cancel()
+
+	loger.Debugln("Updating playlist in the database")
+
+	playlistDB := playlistToDB(playlist)
+
+	err := s.db.QueryRow(queryCtx, "UPDATE public.playlists SET name = $2, description = $3 WHERE id = $1::uuid RETURNING id",
+		playlistDB.ID, playlistDB.Name, playlistDB.Description).Scan(&playlistDB.ID)
+	if err != nil {
+		loger.Errorf("Error updating playlist in the database: %v", err.Error())
+		return playlist, dbError(err, "playlist")
+	}
+
+	loger.Debugln("Playlist updated in the database")
+	return s.ReadPlaylist(ctx, playlistDB.ID)
+}
+
+// DeletePlaylist : Удаление плейлиста вместе с записями из базы данных
+func (s Storage) DeletePlaylist(ctx context.Context, guid string) error {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "DeletePlaylist")
+	defer cancel()
+
+	loger.Debugln("Deleting playlist in the database")
+
+	err := s.db.QueryRow(ctx, "DELETE FROM public.playlists WHERE id = $1::uuid RETURNING id", guid).Scan(&guid)
+	if err != nil {
+		loger.Errorf("Error deleting playlist in the database: %v", err.Error())
+		return dbError(err, "playlist")
+	}
+
+	loger.Debugln("Playlist deleted in the database")
+	return nil
+}
+
+// AddPlaylistEntry : Вставка песни в плейлист на позицию position, записи начиная с нее сдвигаются.
+// position равный 0 добавляет песню в конец
+func (s Storage) AddPlaylistEntry(ctx context.Context, guid string, songID string, position int) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	queryCtx, cancel := s.queryContext(ctx, "AddPlaylistEntry")
+	defer cancel()
+
+	loger.Debugln("Adding playlist entry in the database")
+
+	err := pgx.BeginFunc(queryCtx, s.db, func(tx pgx.Tx) error {
+		count, err := lockPlaylist(queryCtx, tx, guid)
+		if err != nil {
+			return err
+		}
+		if position == 0 {
+			position = count + 1
+		}
+		if position > count+1 {
+			return invalidPosition(count + 1)
+		}
+		if err := checkSongs(queryCtx, tx, []string{songID}); err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(queryCtx, "UPDATE public.playlist_entries SET position = position + 1 WHERE playlist_id = $1::uuid AND position >= $2",
+			guid, position)
+		if err != nil {
+			return err
+		}
+		_, err = tx.Exec(queryCtx, "INSERT INTO public.playlist_entries (playlist_id, song_id, position) VALUES ($1::uuid, $2::uuid, $3)",
+			guid, songID, position)
+		return err
+	})
+	if err != nil {
+		loger.Errorf("Error adding playlist entry in the database: %v", err.Error())
+		return models.Playlist{}, dbError(err, "playlist")
+	}
+
+	loger.Debugln("Playlist entry added in the database")
+	return s.ReadPlaylist(ctx, guid)
+}
+
+// RemovePlaylistEntry : Удаление записи плейлиста на позиции position, следующие записи сдвигаются триггером
+func (s Storage) RemovePlaylistEntry(ctx context.Context, guid string, position int) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	queryCtx, cancel := s.queryContext(ctx, "RemovePlaylistEntry")
+	defer cancel()
+
+	loger.Debugln("Removing playlist entry in the database")
+
+	err := pgx.BeginFunc(queryCtx, s.db, func(tx pgx.Tx) error {
+		if _, err := lockPlaylist(queryCtx, tx, guid); err != nil {
+			return err
+		}
+		var entryID string
+		err := tx.QueryRow(queryCtx, "DELETE FROM public.playlist_entries WHERE playlist_id = $1::uuid AND position = $2 RETURNING id",
+			guid, position).Scan(&entryID)
+		return dbError(err, "entry")
+	})
+	if err != nil {
+		loger.Errorf("Error removing playlist entry in the database: %v", err.Error())
+		return models.Playlist{}, dbError(err, "playlist")
+	}
+
+	loger.Debugln("Playlist entry removed in the database")
+	return s.ReadPlaylist(ctx, guid)
+}
+
+// MovePlaylistEntry : Перемещение записи плейлиста с позиции from на позицию to,
+// записи между ними сдвигаются на одну позицию
+func (s Storage) MovePlaylistEntry(ctx context.Context, guid string, from int, to int) (models.Playlist, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	queryCtx, cancel := s.queryContext(ctx, "MovePlaylistEntry")
+	defer cancel()
+
+	loger.Debugln("Moving playlist entry in the database")
+
+	err := pgx.BeginFunc(queryCtx, s.db, func(tx pgx.Tx) error {
+		count, err := lockPlaylist(queryCtx, tx, guid)
+		if err != nil {
+			return err
+		}
+		if from > count {
+			return dbError(pgx.ErrNoRows, "entry")
+		}
+		if to > count {
+			return invalidPosition(count)
+		}
+
+		_, err = tx.Exec(queryCtx, `UPDATE public.playlist_entries
+			SET position = CASE WHEN position = $2 THEN $3 WHEN $2 < $3 THEN position - 1 ELSE position + 1 END
+			WHERE playlist_id = $1::uuid AND position BETWEEN least($2, $3) AND greatest($2, $3)`,
+			guid, from, to)
+		return err
+	})
+	if err != nil {
+		loger.Errorf("Error moving playlist entry in the database: %v", err.Error())
+		return models.Playlist{}, dbError(err, "playlist")
+	}
+
+	loger.Debugln("Playlist entry moved in the database")
+	return s.ReadPlaylist(ctx, guid)
+}
+
+// lockPlaylist : Блокировка плейлиста до конца транзакции, чтобы позиции не менялись параллельно.
+// Возвращает число записей плейлиста, включая записи песен из корзины
+func lockPlaylist(ctx context.Context, tx pgx.Tx, guid string) (count int, err error) {
+	if err = tx.QueryRow(ctx, "SELECT id FROM public.playlists WHERE id = $1::uuid FOR UPDATE", guid).Scan(&guid); err != nil {
+		return 0, dbError(err, "playlist")
+	}
+	err = tx.QueryRow(ctx, "SELECT count(*) FROM public.playlist_entries WHERE playlist_id = $1::uuid", guid).Scan(&count)
+	return count, err
+}
+
+// invalidPosition : Ошибка позиции за пределами плейлиста
+func invalidPosition(last int) error {
+	return models.NewError(models.ErrValidation, models.CodeInvalidPosition, fmt.Sprintf("Position must be between 1 and %v", last), nil)
+}
+
+// playlistToDB : Преобразование плейлиста в формат базы данных, пустые поля становятся NULL
+func playlistToDB(playlist models.Playlist) models.PlaylistDB {
+	return models.PlaylistDB{
+		ID:          playlist.ID,
+		Name:        playlist.Name,
+		Description: sql.NullString{String: playlist.Description, Valid: playlist.Description != ""},
+		Owner:       sql.NullString{String: playlist.Owner, Valid: playlist.Owner != ""},
+	}
+}
+
+// playlistFromDB : Преобразование плейлиста из формата базы данных
+func playlistFromDB(playlistDB models.PlaylistDB) models.Playlist {
+	return models.Playlist{
+		ID:          playlistDB.ID,
+		Name:        playlistDB.Name,
+		Description: playlistDB.Description.String,
+		Owner:       playlistDB.Owner.String,
+	}
+}
@@ -3,8 +3,11 @@ package storage
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
+
+	"EffectiveMobile/internal/tracing"
 )
 
 var dbPool *pgxpool.Pool
@@ -16,12 +19,35 @@ type Config struct {
 	Password string
 	Name     string
 	SSLMode  string
+	// MaxConns, MinConns, MaxConnLifetime, MaxConnIdleTime : размер пула, 0 оставляет значения pgx по умолчанию
+	MaxConns        int32
+	MinConns        int32
+	MaxConnLifetime time.Duration
+	MaxConnIdleTime time.Duration
 }
 
-// NewPostgresDB : подключение к базе данных
+// NewPostgresDB : подключение к базе данных. Каждый SQL запрос пула получает спан трассировки
 func NewPostgresDB(config Config) (*pgxpool.Pool, error) {
 	databaseURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s", config.Username, config.Password, config.Host, config.Port, config.Name, config.SSLMode)
-	dbPool, err = pgxpool.New(context.Background(), databaseURL)
+	poolConfig, err := pgxpool.ParseConfig(databaseURL)
+	if err != nil {
+		return nil, err
+	}
+	poolConfig.ConnConfig.Tracer = tracing.PgxTracer{}
+	if config.MaxConns > 0 {
+		poolConfig.MaxConns = config.MaxConns
+	}
+	if config.MinConns > 0 {
+		poolConfig.MinConns = config.MinConns
+	}
+	if config.MaxConnLifetime > 0 {
+		poolConfig.MaxConnLifetime = config.MaxConnLifetime
+	}
+	if config.MaxConnIdleTime > 0 {
+		poolConfig.MaxConnIdleTime = config.MaxConnIdleTime
+	}
+
+	dbPool, err = pgxpool.NewWithConfig(context.Background(), poolConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,96 @@
+package storage
+
+import (
+	"context"
+
+	"github.com/jackc/pgx/v5"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// revisionColumns : колонки снимка песни в таблице song_revisions
+const revisionColumns = "song_id AS id, song_name, artist_name, release_date, song_text, link, version, revision, operation, request_id, actor, created_at"
+
+// withAudit : Выполнение изменения песен в транзакции. Request ID и автор запроса передаются
+// триггеру журнала ревизий через локальные для транзакции настройки app.request_id и app.actor
+func (s Storage) withAudit(ctx context.Context, fn func(tx pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, s.db, func(tx pgx.Tx) error {
+		_, err := tx.Exec(ctx, "SELECT set_config('app.request_id', $1, true), set_config('app.actor', $2, true)",
+			reqctx.RequestID(ctx), reqctx.Actor(ctx))
+		if err != nil {
+			return err
+		}
+		return fn(tx)
+	})
+}
+
+// GetSongRevisions : Получение журнала ревизий песни из базы данных, от первой к последней.
+// Журнал сохраняется и после удаления песни
+func (s Storage) GetSongRevisions(ctx context.Context, guid string) (result []models.SongRevision, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetSongRevisions")
+	defer cancel()
+
+	loger.Debugln("Reading song revisions from the database")
+
+	rows, err := s.db.Query(ctx, "SELECT "+revisionColumns+" FROM public.song_revisions WHERE song_id = $1::uuid ORDER BY revision", guid)
+	if err != nil {
+		loger.Errorf("Error getting song revisions from the database: %v", err.Error())
+		return result, dbError(err, "song")
+	}
+
+	revisionsDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.SongRevisionDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "song")
+	}
+
+	if len(revisionsDB) == 0 {
+		return result, dbError(pgx.ErrNoRows, "song")
+	}
+
+	result = make([]models.SongRevision, 0, len(revisionsDB))
+	for _, revisionDB := range revisionsDB {
+		result = append(result, revisionFromDB(revisionDB))
+	}
+
+	loger.Debugln("Song revisions read from the database")
+	return result, nil
+}
+
+// GetSongRevision : Получение ревизии песни по ее номеру из базы данных
+func (s Storage) GetSongRevision(ctx context.Context, guid string, revision int) (result models.SongRevision, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetSongRevision")
+	defer cancel()
+
+	loger.Debugln("Reading song revision from the database")
+
+	rows, err := s.db.Query(ctx, "SELECT "+revisionColumns+" FROM public.song_revisions WHERE song_id = $1::uuid AND revision = $2", guid, revision)
+	if err != nil {
+		loger.Errorf("Error getting song revision from the database: %v", err.Error())
+		return result, dbError(err, "revision")
+	}
+
+	revisionDB, err := pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[models.SongRevisionDB])
+	if err != nil {
+		loger.Errorf("Error getting song revision from the database: %v", err.Error())
+		return result, dbError(err, "revision")
+	}
+
+	loger.Debugln("Song revision read from the database")
+	return revisionFromDB(revisionDB), nil
+}
+
+// revisionFromDB : Преобразование ревизии из формата базы данных
+func revisionFromDB(revisionDB models.SongRevisionDB) models.SongRevision {
+	return models.SongRevision{
+		Revision:  revisionDB.Revision,
+		Operation: revisionDB.Operation,
+		Song:      songFromDB(revisionDB.SongDB),
+		RequestID: revisionDB.RequestID.String,
+		Actor:     revisionDB.Actor.String,
+		CreatedAt: revisionDB.CreatedAt,
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -14,67 +15,167 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 
+	"EffectiveMobile/internal/metrics"
 	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+	"EffectiveMobile/internal/tracing"
 )
 
 var (
-	err  error
 	psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
+	// headlineOptions : параметры ts_headline для фрагментов текста в результатах поиска
+	headlineOptions = `StartSel=<b>, StopSel=</b>, MaxFragments=2, MinWords=5, MaxWords=20, FragmentDelimiter=" ... "`
+
+	// mapDB : соответствие параметров API колонкам таблицы songs. По artist фильтруются песни исполнителя
+	mapDB = map[string]string{
+		"id":      "id",
+		"song":    "song_name",
+		"group":   "artist_name",
+		"release": "release_date",
+		"text":    "song_text",
+		"link":    "link",
+		"artist":  "artist_id",
+	}
 )
 
 type Storage struct {
-	db    *pgxpool.Pool
-	loger *zap.SugaredLogger
+	db           *pgxpool.Pool
+	queryTimeout time.Duration
+	importConfig ImportConfig
+	loger        *zap.SugaredLogger
 	SongStorage
 }
+
+// ImportConfig : ограничения пакетного импорта песен
+type ImportConfig struct {
+	// Timeout : ограничение времени импорта вместо queryTimeout, 0 - только контекст запроса
+	Timeout time.Duration
+	// BatchSize : число строк в одном COPY, 0 - все строки одним COPY
+	BatchSize int
+}
 type SongStorage interface {
-	CreateSong(song models.SongRequest, reqID string) (string, error)
-	ReadSong(guid string, reqID string) (models.Song, error)
-	UpdateSong(song models.Song, reqID string) (models.Song, error)
-	DeleteSong(guid string, reqID string) (models.SongResponse, error)
-	GetSongInfo(song models.SongRequest, reqID string) (models.SongInfoResponse, error)
-	GetSongsList(reqID string) (models.SongsListResponse, error)
-	GetSongCouplet(guid string, id string) (string, error)
+	CreateSong(ctx context.Context, song models.Song) (string, error)
+	ReadSong(ctx context.Context, guid string) (models.Song, error)
+	UpdateSong(ctx context.Context, song models.Song, ifMatch []int) (models.Song, error)
+	PatchSong(ctx context.Context, guid string, patch models.SongPatch, ifMatch []int) (models.Song, error)
+	DeleteSong(ctx context.Context, guid string, ifMatch []int) (models.SongResponse, error)
+	GetSongInfo(ctx context.Context, song models.SongRequest) (models.SongInfoResponse, error)
+	GetSongsList(ctx context.Context, sortOptions models.SortOptions, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsListResponse, *models.PageCursor, error)
+	GetSongLyrics(ctx context.Context, guid string) (string, []models.LyricsSection, error)
+	SearchSongs(ctx context.Context, text string, lang string, paginationOptions models.PaginationOptions, filterOptions map[string]string) (models.SongsSearchResponse, error)
+	GetSongRevisions(ctx context.Context, guid string) ([]models.SongRevision, error)
+	GetSongRevision(ctx context.Context, guid string, revision int) (models.SongRevision, error)
+	GetTrash(ctx context.Context, paginationOptions models.PaginationOptions) (models.TrashResponse, error)
+	RestoreSong(ctx context.Context, guid string) (models.Song, error)
+	PurgeSongs(ctx context.Context, deletedBefore time.Time) (int64, error)
+	ImportSongs(ctx context.Context, songs []models.Song, dryRun bool) ([]models.Song, error)
+	ExportSongs(ctx context.Context, sortOptions models.SortOptions, filterOptions map[string]string, fn func(song models.Song) error) error
+	CreateArtist(ctx context.Context, name string) (models.Artist, error)
+	ReadArtist(ctx context.Context, guid string) (models.Artist, error)
+	GetArtistsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.ArtistsListResponse, error)
+	UpdateArtist(ctx context.Context, artist models.Artist) (models.Artist, error)
+	DeleteArtist(ctx context.Context, guid string) error
+	CreateAlbum(ctx context.Context, album models.Album, tracks []string) (string, error)
+	ReadAlbum(ctx context.Context, guid string) (models.Album, error)
+	GetAlbumsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.AlbumsListResponse, error)
+	UpdateAlbum(ctx context.Context, album models.Album, tracks []string) (models.Album, error)
+	DeleteAlbum(ctx context.Context, guid string) error
+	SetAlbumTracks(ctx context.Context, guid string, tracks []string) (models.Album, error)
+	AddAlbumTrack(ctx context.Context, guid string, songID string) (models.Album, error)
+	CreatePlaylist(ctx context.Context, playlist models.Playlist) (string, error)
+	ReadPlaylist(ctx context.Context, guid string) (models.Playlist, error)
+	GetPlaylistsList(ctx context.Context, paginationOptions models.PaginationOptions) (models.PlaylistsListResponse, error)
+	UpdatePlaylist(ctx context.Context, playlist models.Playlist) (models.Playlist, error)
+	DeletePlaylist(ctx context.Context, guid string) error
+	AddPlaylistEntry(ctx context.Context, guid string, songID string, position int) (models.Playlist, error)
+	RemovePlaylistEntry(ctx context.Context, guid string, position int) (models.Playlist, error)
+	MovePlaylistEntry(ctx context.Context, guid string, from int, to int) (models.Playlist, error)
+	CreateAPIKey(ctx context.Context, key models.APIKey, hash string) (models.APIKey, error)
+	GetAPIKeysList(ctx context.Context, paginationOptions models.PaginationOptions) (models.APIKeysListResponse, error)
+	RotateAPIKey(ctx context.Context, guid string, prefix string, hash string) (models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, guid string) error
+	UseAPIKey(ctx context.Context, hash string) (models.APIKey, error)
 }
 
-func NewStorage(db *pgxpool.Pool, loger *zap.SugaredLogger) *Storage {
+// NewStorage : queryTimeout ограничивает время выполнения каждого метода хранилища, 0 - без ограничения.
+// Импорт ограничен отдельно настройками importConfig
+func NewStorage(db *pgxpool.Pool, queryTimeout time.Duration, importConfig ImportConfig, loger *zap.SugaredLogger) *Storage {
 	return &Storage{
-		db:    db,
-		loger: loger,
+		db:           db,
+		queryTimeout: queryTimeout,
+		importConfig: importConfig,
+		loger:        loger,
+	}
+}
+
+// queryContext : Контекст запроса к базе данных, отменяемый вместе с HTTP запросом или по таймауту.
+// Метод method получает спан, в который вложены спаны его SQL запросов. Отмена контекста
+// в конце метода завершает спан и учитывает время выполнения в метриках
+func (s Storage) queryContext(ctx context.Context, method string) (context.Context, context.CancelFunc) {
+	return s.timeoutContext(ctx, method, s.queryTimeout)
+}
+
+// timeoutContext : queryContext с таймаутом timeout вместо queryTimeout, 0 - без ограничения
+func (s Storage) timeoutContext(ctx context.Context, method string, timeout time.Duration) (context.Context, context.CancelFunc) {
+	start := time.Now()
+	ctx, span := tracing.Start(ctx, "Storage."+method)
+	var cancel context.CancelFunc
+	if timeout <= 0 {
+		ctx, cancel = context.WithCancel(ctx)
+	} else {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	return ctx, func() {
+		cancel()
+		span.End()
+		metrics.ObserveStorage(method, time.Since(start))
 	}
 }
 
 // CreateSong : Создание песни в базе данных
-func (s Storage) CreateSong(song models.SongRequest, reqID string) (string, error) {
-	s.loger.Debugf("RequestID: %v. Creating song in the database", reqID)
+func (s Storage) CreateSong(ctx context.Context, song models.Song) (string, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "CreateSong")
+	defer cancel()
+
+	loger.Debugln("Creating song in the database")
 	result := models.SongResponse{}
 
-	err = s.db.QueryRow(context.Background(), "INSERT INTO public.songs (song_name, artist_name) VALUES ($1, $2) RETURNING id",
-		song.Name, song.Artist).Scan(&result.ID)
+	songDB := songToDB(song)
+
+	err := s.withAudit(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "INSERT INTO public.songs (song_name, artist_name, release_date, song_text, link, lyrics) VALUES ($1, $2, $3, $4, $5, $6) RETURNING id",
+			songDB.Name, songDB.Artist, songDB.Release, songDB.Text, songDB.Link, lyricsToDB(song.Lyrics)).Scan(&result.ID)
+	})
 
 	if err != nil {
-		s.loger.Errorf("Error creating song in the database: %v", err.Error())
-		return "", err
+		loger.Errorf("Error creating song in the database: %v", err.Error())
+		return "", dbError(err, "song")
 	}
 
-	s.loger.Debugf("RequestID: %v. Song created in the database", reqID)
+	loger.Debugln("Song created in the database")
 	return result.ID, nil
 }
 
 // ReadSong : Получение песни по ее ID из базы данных
-func (s Storage) ReadSong(guid string, reqID string) (result models.Song, err error) {
-	s.loger.Debugf("RequestID: %v. Reading song from the database", reqID)
+func (s Storage) ReadSong(ctx context.Context, guid string) (result models.Song, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "ReadSong")
+	defer cancel()
+
+	loger.Debugln("Reading song from the database")
 	resultDB := models.SongDB{}
 
-	err = s.db.QueryRow(context.Background(), "SELECT id, song_name, artist_name, release_date, song_text, link FROM public.songs WHERE id = $1",
-		guid).Scan(&resultDB.ID, &resultDB.Name, &resultDB.Artist, &resultDB.Release, &resultDB.Text, &resultDB.Link)
+	err = s.db.QueryRow(ctx, "SELECT id, song_name, artist_name, release_date, song_text, link, version FROM public.songs WHERE id = $1 AND deleted_at IS NULL",
+		guid).Scan(&resultDB.ID, &resultDB.Name, &resultDB.Artist, &resultDB.Release, &resultDB.Text, &resultDB.Link, &resultDB.Version)
 
 	if err != nil {
-		s.loger.Errorf("Error getting song from the database: %v", err.Error())
-		return result, err
+		loger.Errorf("Error getting song from the database: %v", err.Error())
+		return result, dbError(err, "song")
 	}
 
-	s.loger.Debugf("SongDB: %v", resultDB)
+	loger.Debugf("SongDB: %v", resultDB)
 
 	var releaseDate, text, link string
 
@@ -101,52 +202,31 @@ func (s Storage) ReadSong(guid string, reqID string) (result models.Song, err er
 		Release: releaseDate,
 		Text:    text,
 		Link:    link,
+		Version: resultDB.Version,
 	}
 
-	s.loger.Debugf("Song: %v", result)
+	loger.Debugf("Song: %v", result)
 
-	s.loger.Debugf("RequestID: %v. Song read from the database", reqID)
+	loger.Debugln("Song read from the database")
 	return result, nil
 }
 
-// UpdateSong : Обновление песни в базе данных
-func (s Storage) UpdateSong(song models.Song, reqID string) (result models.Song, err error) {
-	s.loger.Debugf("RequestID: %v. Updating song in the database", reqID)
-	resultDB := models.SongDB{}
-	var releaseDate time.Time
-	var timeVaild, textValid, linkValid bool
+// UpdateSong : Обновление песни в базе данных. Если ifMatch не nil, песня обновляется,
+// только когда ее текущая версия есть в списке
+func (s Storage) UpdateSong(ctx context.Context, song models.Song, ifMatch []int) (result models.Song, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "UpdateSong")
+	defer cancel()
 
-	if song.Release != "" {
-		releaseDate, _ = time.Parse("02.01.2006", song.Release)
-		timeVaild = true
-	} else {
-		timeVaild = false
-		releaseDate = time.Time{}
-	}
+	loger.Debugln("Updating song in the database")
+	resultDB := models.SongDB{}
 
-	if song.Text != "" {
-		textValid = true
-	} else {
-		textValid = false
-	}
+	songDB := songToDB(song)
 
-	if song.Link != "" {
-		linkValid = true
-	} else {
-		linkValid = false
-	}
-
-	songDB := models.SongDB{
-		ID:      song.ID,
-		Name:    song.Name,
-		Artist:  song.Artist,
-		Release: sql.NullTime{Time: releaseDate, Valid: timeVaild},
-		Text:    sql.NullString{String: song.Text, Valid: textValid},
-		Link:    sql.NullString{String: song.Link, Valid: linkValid},
-	}
-
-	err = s.db.QueryRow(context.Background(), "UPDATE public.songs SET song_name= $1, artist_name= $2, release_date= $3, song_text= $4, link= $5  WHERE id= $6  RETURNING id,song_name,artist_name,release_date,song_text,link",
-		songDB.Name, songDB.Artist, songDB.Release, songDB.Text, songDB.Link, songDB.ID).Scan(&resultDB.ID, &resultDB.Name, &resultDB.Artist, &resultDB.Release, &resultDB.Text, &resultDB.Link)
+	err = s.withAudit(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "UPDATE public.songs SET song_name= $1, artist_name= $2, release_date= $3, song_text= $4, link= $5, lyrics= $8, version= version + 1  WHERE id= $6 AND deleted_at IS NULL AND ($7::int[] IS NULL OR version = ANY($7))  RETURNING id,song_name,artist_name,release_date,song_text,link,version",
+			songDB.Name, songDB.Artist, songDB.Release, songDB.Text, songDB.Link, songDB.ID, ifMatch, lyricsToDB(song.Lyrics)).Scan(&resultDB.ID, &resultDB.Name, &resultDB.Artist, &resultDB.Release, &resultDB.Text, &resultDB.Link, &resultDB.Version)
+	})
 
 	var rlsDate, text, link string
 
@@ -173,45 +253,135 @@ func (s Storage) UpdateSong(song models.Song, reqID string) (result models.Song,
 		Release: rlsDate,
 		Text:    text,
 		Link:    link,
+		Version: resultDB.Version,
 	}
 
 	if err != nil {
-		s.loger.Errorf("Error updating song in the database: %v", err.Error())
-		return result, err
+		loger.Errorf("Error updating song in the database: %v", err.Error())
+		return result, s.versionError(ctx, songDB.ID, err)
 	}
 
-	s.loger.Debugf("RequestID: %v. Song updated in the database", reqID)
+	loger.Debugln("Song updated in the database")
 	return result, nil
 }
 
-// DeleteSong : Удаление песни в базе данных
-func (s Storage) DeleteSong(guid string, reqID string) (models.SongResponse, error) {
-	s.loger.Debugf("RequestID: %v. Deleting song in the database", reqID)
-	result := models.SongResponse{}
+// PatchSong : Частичное обновление песни в базе данных, меняются только переданные в патче поля.
+// ifMatch проверяется так же, как в UpdateSong
+func (s Storage) PatchSong(ctx context.Context, guid string, patch models.SongPatch, ifMatch []int) (result models.Song, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "PatchSong")
+	defer cancel()
+
+	loger.Debugln("Patching song in the database")
+	resultDB := models.SongDB{}
+
+	columns := []struct {
+		name  string
+		field models.PatchField
+	}{
+		{"song_name", patch.Name},
+		{"artist_name", patch.Artist},
+		{"release_date", patch.Release},
+		{"song_text", patch.Text},
+		{"link", patch.Link},
+	}
+
+	ub := psql.Update("public.songs").
+		Set("version", sq.Expr("version + 1")).
+		Where(sq.Eq{"id": guid}).
+		Where(sq.Eq{"deleted_at": nil}).
+		Where("(?::int[] IS NULL OR version = ANY(?))", ifMatch, ifMatch).
+		Suffix("RETURNING id, song_name, artist_name, release_date, song_text, link, version")
+
+	changed := false
+	for _, column := range columns {
+		if !column.field.Set {
+			continue
+		}
+		changed = true
+
+		switch {
+		case column.field.Null || column.field.Value == "":
+			ub = ub.Set(column.name, nil)
+		case column.name == "release_date":
+			releaseDate, _ := time.Parse("02.01.2006", column.field.Value)
+			ub = ub.Set(column.name, releaseDate)
+		default:
+			ub = ub.Set(column.name, column.field.Value)
+		}
+	}
+
+	if patch.Text.Set {
+		ub = ub.Set("lyrics", lyricsToDB(patch.Lyrics))
+	}
 
-	err = s.db.QueryRow(context.Background(), "DELETE FROM public.songs WHERE id = $1::uuid RETURNING id",
-		guid).Scan(&result.ID)
+	// Пустой патч не меняет песню и ее версию
+	if !changed {
+		result, err = s.ReadSong(ctx, guid)
+		if err == nil && ifMatch != nil && !slices.Contains(ifMatch, result.Version) {
+			return models.Song{}, versionMismatch()
+		}
+		return result, err
+	}
 
+	query, args, err := ub.ToSql()
 	if err != nil {
-		s.loger.Errorf("Error deleting song in the database: %v", err.Error())
+		loger.Errorf("Error building query: %v", err)
 		return result, err
 	}
+	loger.Debugf("Query: %v", query)
+
+	err = s.withAudit(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, query, args...).Scan(&resultDB.ID, &resultDB.Name, &resultDB.Artist, &resultDB.Release, &resultDB.Text, &resultDB.Link, &resultDB.Version)
+	})
+	if err != nil {
+		loger.Errorf("Error patching song in the database: %v", err.Error())
+		return result, s.versionError(ctx, guid, err)
+	}
+
+	loger.Debugln("Song patched in the database")
+	return songFromDB(resultDB), nil
+}
+
+// DeleteSong : Перемещение песни в корзину. Удаленная песня не видна в остальных методах,
+// пока ее не восстановят или не удалят окончательно. ifMatch проверяется так же, как в UpdateSong
+func (s Storage) DeleteSong(ctx context.Context, guid string, ifMatch []int) (models.SongResponse, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "DeleteSong")
+	defer cancel()
+
+	loger.Debugln("Deleting song in the database")
+	result := models.SongResponse{}
+
+	err := s.withAudit(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "UPDATE public.songs SET deleted_at = (now() at time zone 'utc'), version = version + 1 WHERE id = $1::uuid AND deleted_at IS NULL AND ($2::int[] IS NULL OR version = ANY($2)) RETURNING id",
+			guid, ifMatch).Scan(&result.ID)
+	})
+
+	if err != nil {
+		loger.Errorf("Error deleting song in the database: %v", err.Error())
+		return result, s.versionError(ctx, guid, err)
+	}
 
-	s.loger.Debugf("RequestID: %v. Song deleted in the database", reqID)
+	loger.Debugln("Song deleted in the database")
 	return result, nil
 }
 
 // GetSongInfo : Получение информации о песни в базе данных
-func (s Storage) GetSongInfo(song models.SongRequest, reqID string) (result models.SongInfoResponse, err error) {
-	s.loger.Debugf("RequestID: %v. Reading song info from the database", reqID)
+func (s Storage) GetSongInfo(ctx context.Context, song models.SongRequest) (result models.SongInfoResponse, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetSongInfo")
+	defer cancel()
+
+	loger.Debugln("Reading song info from the database")
 	resultDB := models.SongInfoResponseDB{}
 
-	err = s.db.QueryRow(context.Background(), "SELECT release_date, song_text, link FROM public.songs WHERE song_name = $1 AND artist_name = $2",
+	err = s.db.QueryRow(ctx, "SELECT release_date, song_text, link FROM public.songs WHERE song_name = $1 AND lower(artist_name) = lower(btrim($2)) AND deleted_at IS NULL",
 		song.Name, song.Artist).Scan(&resultDB.Release, &resultDB.Text, &resultDB.Link)
 
 	if err != nil {
-		s.loger.Errorf("Error getting song info from the database: %v", err.Error())
-		return result, err
+		loger.Errorf("Error getting song info from the database: %v", err.Error())
+		return result, dbError(err, "song")
 	}
 
 	var rlsDate, text, link string
@@ -238,163 +408,364 @@ func (s Storage) GetSongInfo(song models.SongRequest, reqID string) (result mode
 		Link:    link,
 	}
 
-	s.loger.Info("Song info: ", result)
+	loger.Info("Song info: ", result)
 
-	s.loger.Debugf("RequestID: %v. Song info read from the database", reqID)
+	loger.Debugln("Song info read from the database")
 	return result, nil
 }
 
-// GetSongsList : Получение списка песен в базе данных
-func (s Storage) GetSongsList(reqID string, sortOptions models.SortOptions, paginationOptions models.PaginationOptions, filterOptions map[string]string) (result models.SongsListResponse, err error) {
+// GetSongsList : Получение списка песен в базе данных.
+// Если после страницы есть еще строки, возвращается курсор на последнюю песню страницы
+func (s Storage) GetSongsList(ctx context.Context, sortOptions models.SortOptions, paginationOptions models.PaginationOptions, filterOptions map[string]string) (result models.SongsListResponse, next *models.PageCursor, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetSongsList")
+	defer cancel()
+
 	var rows pgx.Rows
 
-	s.loger.Debugf("RequestID: %v. Reading songs list from the database", reqID)
+	loger.Debugln("Reading songs list from the database")
 	resultDB := models.SongsListResponseDB{}
 
 	limit, _ := strconv.Atoi(paginationOptions.Limit)
 
-	mapDB := map[string]string{
-		"id":      "id",
-		"song":    "song_name",
-		"group":   "artist_name",
-		"release": "release_date",
-		"text":    "song_text",
-		"link":    "link",
+	sb := psql.Select("id", "song_name", "artist_name", "release_date", "song_text", "link", "version").
+		From("public.songs").
+		Where(sq.Eq{"deleted_at": nil})
+
+	sb, err = applyFilters(sb, filterOptions)
+	if err != nil {
+		loger.Errorf("Error applying filters: %v", err)
+		return result, nil, err
 	}
 
-	sb := psql.Select("id", "song_name", "artist_name", "release_date", "song_text", "link").
-		From("public.songs")
-
-	if len(filterOptions) != 0 {
-		for key, value := range filterOptions {
-			param := strings.Split(value, ":")
-			switch len(param) {
-			case 1:
-				sb = sb.Where(sq.Eq{mapDB[key]: value})
-			case 2:
-				switch param[0] {
-				case "eq":
-					sb = sb.Where(sq.Eq{mapDB[key]: param[1]})
-				case "nq":
-					sb = sb.Where(sq.NotEq{mapDB[key]: param[1]})
-				case "gt":
-					sb = sb.Where(sq.Gt{mapDB[key]: param[1]})
-				case "gte":
-					sb = sb.Where(sq.GtOrEq{mapDB[key]: param[1]})
-				case "lt":
-					sb = sb.Where(sq.Lt{mapDB[key]: param[1]})
-				case "lte":
-					sb = sb.Where(sq.LtOrEq{mapDB[key]: param[1]})
-				case "like":
-					sb = sb.Where(sq.Like{mapDB[key]: param[1]})
-				case "ilike":
-					sb = sb.Where(sq.ILike{mapDB[key]: param[1]})
-				}
-			default:
-				s.loger.Errorf("Wrong filterOptions format")
-				return result, err
-			}
-		}
+	// Сортировка всегда дополняется id, чтобы порядок строк был однозначным
+	sortColumn := mapDB[sortOptions.Field]
+	if sortColumn == "id" {
+		sb = sb.OrderBy(fmt.Sprintf("id %v", sortOptions.Order))
+	} else {
+		sb = sb.OrderBy(fmt.Sprintf("%v %v NULLS LAST", sortColumn, sortOptions.Order), fmt.Sprintf("id %v", sortOptions.Order))
 	}
-	sb = sb.OrderBy(fmt.Sprintf("%v %v", mapDB[sortOptions.Field], sortOptions.Order))
-	sb = sb.Limit(uint64(limit))
-	if paginationOptions.Offset != "" {
+	// Запрашиваем на одну строку больше, чтобы узнать, есть ли следующая страница
+	sb = sb.Limit(uint64(limit + 1))
+	if paginationOptions.Cursor != nil {
+		sb = sb.Where(keysetCondition(sortColumn, *paginationOptions.Cursor))
+	} else if paginationOptions.Offset != "" {
 		offset, err := strconv.Atoi(paginationOptions.Offset)
 		if err != nil {
-			s.loger.Errorf("Error converting offset to int: %v", err)
-			return result, err
+			loger.Errorf("Error converting offset to int: %v", err)
+			return result, nil, models.NewError(models.ErrValidation, models.CodeInvalidPagination, "Offset must be a number", err)
 		}
 		sb = sb.Offset(uint64(offset))
-	} else {
-		pToken, err := strconv.Atoi(paginationOptions.PageToken)
-		if err != nil {
-			s.loger.Errorf("Error converting page_token to int: %v", err)
-			return result, err
-		}
-		// TODO
-		s.loger.Debugf("Page token: %v", pToken)
 	}
 
 	query, args, err := sb.ToSql()
 	if err != nil {
-		s.loger.Errorf("Error building query: %v", err)
-		return result, err
+		loger.Errorf("Error building query: %v", err)
+		return result, nil, err
 	}
-	s.loger.Debugf("Query: %v", query)
-	s.loger.Debugf("Args: %v", args)
-	s.loger.Debugf("len args: %v", len(args))
+	loger.Debugf("Query: %v", query)
+	loger.Debugf("Args: %v", args)
+	loger.Debugf("len args: %v", len(args))
 
 	if len(args) == 0 {
-		rows, err = s.db.Query(context.Background(), query)
+		rows, err = s.db.Query(ctx, query)
 	} else {
-		rows, err = s.db.Query(context.Background(), query, args...)
+		rows, err = s.db.Query(ctx, query, args...)
 	}
 	if err != nil {
-		s.loger.Errorf("Error getting songs list from the database: %v", err.Error())
-		return result, err
+		loger.Errorf("Error getting songs list from the database: %v", err.Error())
+		return result, nil, dbError(err, "song")
 	}
 
 	resultDB.Songs, err = pgx.CollectRows(rows, pgx.RowToStructByName[models.SongDB])
 	if err != nil {
-		s.loger.Errorf("Error collecting rows: %v", err.Error())
-		return result, err
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, nil, dbError(err, "song")
+	}
+
+	if len(resultDB.Songs) > limit {
+		resultDB.Songs = resultDB.Songs[:limit]
+		if limit > 0 {
+			last := resultDB.Songs[limit-1]
+			next = &models.PageCursor{
+				Field: sortOptions.Field,
+				Order: sortOptions.Order,
+				Value: cursorValue(last, sortColumn),
+				ID:    last.ID,
+			}
+		}
 	}
 
 	for _, songDB := range resultDB.Songs {
-		var rlsDate, text, link string
+		result.Songs = append(result.Songs, songFromDB(songDB))
+	}
 
-		if songDB.Release.Valid {
-			rlsDate = songDB.Release.Time.Format("02.01.2006")
-		} else {
-			rlsDate = ""
-		}
-		if songDB.Text.Valid {
-			text = songDB.Text.String
-		} else {
-			text = ""
-		}
-		if songDB.Link.Valid {
-			link = songDB.Link.String
-		} else {
-			link = ""
-		}
+	loger.Debugln("Songs list read from the database")
+	return result, next, nil
+}
 
-		row := models.Song{
-			ID:      songDB.ID,
-			Name:    songDB.Name,
-			Artist:  songDB.Artist,
-			Release: rlsDate,
-			Text:    text,
-			Link:    link,
-		}
-		result.Songs = append(result.Songs, row)
+// GetSongLyrics : Получение текста песни и его сохраненного разбора из базы данных.
+// Разбор равен nil, если песню записали до его появления
+func (s Storage) GetSongLyrics(ctx context.Context, guid string) (text string, lyrics []models.LyricsSection, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetSongLyrics")
+	defer cancel()
+
+	loger.Debugln("Reading song lyrics from the database")
+
+	var textDB sql.NullString
+
+	err = s.db.QueryRow(ctx, "SELECT song_text, lyrics FROM public.songs WHERE id = $1::uuid AND deleted_at IS NULL", guid).Scan(&textDB, &lyrics)
+	if err != nil {
+		loger.Errorf("Error getting song lyrics from the database: %v", err.Error())
+		return text, nil, dbError(err, "song")
 	}
 
-	s.loger.Debugf("RequestID: %v. Songs list read from the database", reqID)
-	return result, nil
+	loger.Debugln("Song lyrics read from the database")
+	return textDB.String, lyrics, nil
+}
+
+// lyricsToDB : Разбор текста для записи в колонку lyrics, пустой разбор записывается как NULL
+func lyricsToDB(lyrics []models.LyricsSection) any {
+	if len(lyrics) == 0 {
+		return nil
+	}
+	return lyrics
+}
+
+// songToDB : Преобразование песни в формат базы данных, пустые поля записываются как NULL
+func songToDB(song models.Song) models.SongDB {
+	var releaseDate time.Time
+	var timeVaild, textValid, linkValid bool
+
+	if song.Release != "" {
+		releaseDate, _ = time.Parse("02.01.2006", song.Release)
+		timeVaild = true
+	} else {
+		timeVaild = false
+		releaseDate = time.Time{}
+	}
+
+	if song.Text != "" {
+		textValid = true
+	} else {
+		textValid = false
+	}
+
+	if song.Link != "" {
+		linkValid = true
+	} else {
+		linkValid = false
+	}
+
+	return models.SongDB{
+		ID:      song.ID,
+		Name:    song.Name,
+		Artist:  song.Artist,
+		Release: sql.NullTime{Time: releaseDate, Valid: timeVaild},
+		Text:    sql.NullString{String: song.Text, Valid: textValid},
+		Link:    sql.NullString{String: song.Link, Valid: linkValid},
+	}
 }
 
-// GetSongCouplet : Получение куплета песни в базе данных
-func (s Storage) GetSongCouplet(guid string, id string) (result string, err error) {
-	s.loger.Debugf("RequestID: %v. Reading song verses from the database with ID=", id)
+// SearchSongs : Полнотекстовый поиск песен с ранжированием и подсветкой совпадений в тексте.
+// lang выбирает конфигурацию поиска: "ru", "en" или обе, если пусто
+func (s Storage) SearchSongs(ctx context.Context, text string, lang string, paginationOptions models.PaginationOptions, filterOptions map[string]string) (result models.SongsSearchResponse, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "SearchSongs")
+	defer cancel()
+
+	loger.Debugln("Searching songs in the database")
 
-	var resultDB sql.NullString
+	limit, _ := strconv.Atoi(paginationOptions.Limit)
+	offset, _ := strconv.Atoi(paginationOptions.Offset)
+
+	var match, rank, headline string
+	switch lang {
+	case "ru":
+		match = "search_ru @@ query_ru"
+		rank = "ts_rank_cd(search_ru, query_ru)"
+		headline = "ts_headline('russian', coalesce(song_text, ''), query_ru, ?)"
+	case "en":
+		match = "search_en @@ query_en"
+		rank = "ts_rank_cd(search_en, query_en)"
+		headline = "ts_headline('english', coalesce(song_text, ''), query_en, ?)"
+	default:
+		match = "(search_ru @@ query_ru OR search_en @@ query_en)"
+		rank = "greatest(ts_rank_cd(search_ru, query_ru), ts_rank_cd(search_en, query_en))"
+		headline = "ts_headline('russian', coalesce(song_text, ''), query_ru || query_en, ?)"
+	}
+
+	sb := psql.Select("id", "song_name", "artist_name", "release_date", "song_text", "link", "version").
+		Column(rank+" AS rank").
+		Column(sq.Expr(headline+" AS snippet", headlineOptions)).
+		From("public.songs").
+		CrossJoin("websearch_to_tsquery('russian', ?) AS query_ru", text).
+		CrossJoin("websearch_to_tsquery('english', ?) AS query_en", text).
+		Where(match).
+		Where(sq.Eq{"deleted_at": nil})
+
+	sb, err = applyFilters(sb, filterOptions)
+	if err != nil {
+		loger.Errorf("Error applying filters: %v", err)
+		return result, err
+	}
 
-	err = s.db.QueryRow(context.Background(), "SELECT song_text FROM public.songs WHERE id = $1::uuid", guid).Scan(&resultDB)
+	sb = sb.OrderBy("rank DESC", "id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset))
 
+	query, args, err := sb.ToSql()
 	if err != nil {
-		s.loger.Errorf("Error getting song verses from the database: %v", err.Error())
+		loger.Errorf("Error building query: %v", err)
 		return result, err
 	}
+	loger.Debugf("Query: %v", query)
+	loger.Debugf("Args: %v", args)
 
-	if resultDB.Valid {
-		result = resultDB.String
-	} else {
-		result = ""
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		loger.Errorf("Error searching songs in the database: %v", err.Error())
+		return result, dbError(err, "song")
+	}
+
+	resultsDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.SongSearchResultDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "song")
 	}
 
-	s.loger.Info("Text: ", result)
+	result.Results = make([]models.SongSearchResult, 0, len(resultsDB))
+	for _, resultDB := range resultsDB {
+		result.Results = append(result.Results, models.SongSearchResult{
+			Song:    songFromDB(resultDB.SongDB),
+			Rank:    resultDB.Rank,
+			Snippet: resultDB.Snippet,
+		})
+	}
 
-	s.loger.Debugf("RequestID: %v. Song verses read from the database", id)
+	loger.Debugf("Songs found in the database: %v", len(result.Results))
 	return result, nil
 }
+
+// keysetCondition : Условие выборки строк, следующих за курсором, в порядке "column NULLS LAST, id"
+func keysetCondition(column string, cursor models.PageCursor) sq.Sqlizer {
+	after := func(column string, value interface{}) sq.Sqlizer {
+		if strings.ToLower(cursor.Order) == "desc" {
+			return sq.Lt{column: value}
+		}
+		return sq.Gt{column: value}
+	}
+
+	if column == "id" {
+		return after("id", cursor.ID)
+	}
+	if cursor.Value == nil {
+		return sq.And{sq.Eq{column: nil}, after("id", cursor.ID)}
+	}
+	return sq.Or{
+		after(column, *cursor.Value),
+		sq.And{sq.Eq{column: *cursor.Value}, after("id", cursor.ID)},
+		sq.Eq{column: nil},
+	}
+}
+
+// cursorValue : Значение колонки сортировки для курсора, nil для NULL
+func cursorValue(song models.SongDB, column string) *string {
+	var value string
+
+	switch column {
+	case "song_name":
+		value = song.Name
+	case "artist_name":
+		value = song.Artist
+	case "release_date":
+		if !song.Release.Valid {
+			return nil
+		}
+		value = song.Release.Time.Format("2006-01-02")
+	case "song_text":
+		if !song.Text.Valid {
+			return nil
+		}
+		value = song.Text.String
+	case "link":
+		if !song.Link.Valid {
+			return nil
+		}
+		value = song.Link.String
+	default:
+		return nil
+	}
+	return &value
+}
+
+// applyFilters : Добавление условий фильтрации вида "operator:value" к запросу.
+// Фильтр album выбирает треки альбома и поддерживает только сравнение на равенство
+func applyFilters(sb sq.SelectBuilder, filterOptions map[string]string) (sq.SelectBuilder, error) {
+	for key, value := range filterOptions {
+		if key == "album" {
+			album, found := strings.CutPrefix(value, "eq:")
+			if !found && strings.Contains(value, ":") {
+				return sb, models.NewError(models.ErrValidation, models.CodeInvalidFilter, "Filter album supports only the eq operator", nil)
+			}
+			sb = sb.Where("songs.id IN (SELECT song_id FROM public.album_tracks WHERE album_id = ?::uuid)", album)
+			continue
+		}
+
+		param := strings.Split(value, ":")
+		switch len(param) {
+		case 1:
+			sb = sb.Where(sq.Eq{mapDB[key]: value})
+		case 2:
+			switch param[0] {
+			case "eq":
+				sb = sb.Where(sq.Eq{mapDB[key]: param[1]})
+			case "nq":
+				sb = sb.Where(sq.NotEq{mapDB[key]: param[1]})
+			case "gt":
+				sb = sb.Where(sq.Gt{mapDB[key]: param[1]})
+			case "gte":
+				sb = sb.Where(sq.GtOrEq{mapDB[key]: param[1]})
+			case "lt":
+				sb = sb.Where(sq.Lt{mapDB[key]: param[1]})
+			case "lte":
+				sb = sb.Where(sq.LtOrEq{mapDB[key]: param[1]})
+			case "like":
+				sb = sb.Where(sq.Like{mapDB[key]: param[1]})
+			case "ilike":
+				sb = sb.Where(sq.ILike{mapDB[key]: param[1]})
+			default:
+				return sb, models.NewError(models.ErrValidation, models.CodeInvalidFilter, fmt.Sprintf("Filter %v has unknown operator %v", key, param[0]), nil)
+			}
+		default:
+			return sb, models.NewError(models.ErrValidation, models.CodeInvalidFilter, fmt.Sprintf("Filter %v must be in operator:value format", key), nil)
+		}
+	}
+	return sb, nil
+}
+
+// songFromDB : Преобразование песни из формата базы данных, NULL поля становятся пустыми строками
+func songFromDB(songDB models.SongDB) models.Song {
+	var rlsDate, text, link string
+
+	if songDB.Release.Valid {
+		rlsDate = songDB.Release.Time.Format("02.01.2006")
+	}
+	if songDB.Text.Valid {
+		text = songDB.Text.String
+	}
+	if songDB.Link.Valid {
+		link = songDB.Link.String
+	}
+
+	return models.Song{
+		ID:      songDB.ID,
+		Name:    songDB.Name,
+		Artist:  songDB.Artist,
+		Release: rlsDate,
+		Text:    text,
+		Link:    link,
+		Version: songDB.Version,
+	}
+}
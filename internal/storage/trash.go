@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"EffectiveMobile/internal/models"
+	"EffectiveMobile/internal/reqctx"
+)
+
+// GetTrash : Получение удаленных песен из базы данных, последние удаленные первыми
+func (s Storage) GetTrash(ctx context.Context, paginationOptions models.PaginationOptions) (result models.TrashResponse, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "GetTrash")
+	defer cancel()
+
+	loger.Debugln("Reading trash from the database")
+
+	limit, _ := strconv.Atoi(paginationOptions.Limit)
+	offset, _ := strconv.Atoi(paginationOptions.Offset)
+
+	query, args, err := psql.Select("id", "song_name", "artist_name", "release_date", "song_text", "link", "version", "deleted_at").
+		From("public.songs").
+		Where("deleted_at IS NOT NULL").
+		OrderBy("deleted_at DESC", "id").
+		Limit(uint64(limit)).
+		Offset(uint64(offset)).
+		ToSql()
+	if err != nil {
+		loger.Errorf("Error building query: %v", err)
+		return result, err
+	}
+	loger.Debugf("Query: %v", query)
+
+	rows, err := s.db.Query(ctx, query, args...)
+	if err != nil {
+		loger.Errorf("Error getting trash from the database: %v", err.Error())
+		return result, dbError(err, "song")
+	}
+
+	songsDB, err := pgx.CollectRows(rows, pgx.RowToStructByName[models.TrashedSongDB])
+	if err != nil {
+		loger.Errorf("Error collecting rows: %v", err.Error())
+		return result, dbError(err, "song")
+	}
+
+	result.Songs = make([]models.TrashedSong, 0, len(songsDB))
+	for _, songDB := range songsDB {
+		result.Songs = append(result.Songs, models.TrashedSong{
+			Song:      songFromDB(songDB.SongDB),
+			DeletedAt: songDB.DeletedAt,
+		})
+	}
+
+	loger.Debugln("Trash read from the database")
+	return result, nil
+}
+
+// RestoreSong : Восстановление песни из корзины. Если за это время создали песню
+// с тем же названием и исполнителем, возвращается конфликт
+func (s Storage) RestoreSong(ctx context.Context, guid string) (result models.Song, err error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "RestoreSong")
+	defer cancel()
+
+	loger.Debugln("Restoring song in the database")
+	resultDB := models.SongDB{}
+
+	err = s.withAudit(ctx, func(tx pgx.Tx) error {
+		return tx.QueryRow(ctx, "UPDATE public.songs SET deleted_at = NULL, version = version + 1 WHERE id = $1::uuid AND deleted_at IS NOT NULL RETURNING id, song_name, artist_name, release_date, song_text, link, version",
+			guid).Scan(&resultDB.ID, &resultDB.Name, &resultDB.Artist, &resultDB.Release, &resultDB.Text, &resultDB.Link, &resultDB.Version)
+	})
+	if err != nil {
+		loger.Errorf("Error restoring song in the database: %v", err.Error())
+		return result, dbError(err, "song")
+	}
+
+	loger.Debugln("Song restored in the database")
+	return songFromDB(resultDB), nil
+}
+
+// PurgeSongs : Окончательное удаление песен, которые лежат в корзине с момента раньше deletedBefore
+func (s Storage) PurgeSongs(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	loger := reqctx.Logger(ctx, s.loger)
+	ctx, cancel := s.queryContext(ctx, "PurgeSongs")
+	defer cancel()
+
+	loger.Debugln("Purging trash in the database")
+
+	var purged int64
+	err := s.withAudit(ctx, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, "DELETE FROM public.songs WHERE deleted_at < $1", deletedBefore)
+		purged = tag.RowsAffected()
+		return err
+	})
+	if err != nil {
+		loger.Errorf("Error purging trash in the database: %v", err.Error())
+		return 0, dbError(err, "song")
+	}
+
+	loger.Debugf("Songs purged from the database: %v", purged)
+	return purged, nil
+}
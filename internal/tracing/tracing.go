@@ -0,0 +1,90 @@
+// Package tracing настраивает трассировку OpenTelemetry: экспорт спанов, распространение
+// контекста W3C traceparent и спаны запросов к базе данных
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentation : имя трассировщика приложения
+const instrumentation = "EffectiveMobile"
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+)
+
+// Config : Exporter - куда отправляются спаны: otlp (адрес из OTEL_EXPORTER_OTLP_ENDPOINT),
+// stdout или none. SampleRatio - доля трассируемых запросов без входящего traceparent
+type Config struct {
+	Exporter    string
+	ServiceName string
+	SampleRatio float64
+}
+
+// Setup : Настройка глобального провайдера трассировки и распространения контекста W3C.
+// Возвращает функцию, которая отправляет оставшиеся спаны при остановке приложения
+func Setup(ctx context.Context, config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	var exporter sdktrace.SpanExporter
+	var err error
+	switch config.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+		exporter, err = otlptracehttp.New(ctx)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	default:
+		return nil, fmt.Errorf("unknown exporter %q, expected otlp, stdout or none", config.Exporter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// OTEL_SERVICE_NAME и OTEL_RESOURCE_ATTRIBUTES переопределяют имя сервиса
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(config.ServiceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start : Новый спан трассировщика приложения
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentation).Start(ctx, name, opts...)
+}
+
+// RecordError : Отметка спана как завершившегося ошибкой err, nil не меняет спан
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
@@ -0,0 +1,59 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"EffectiveMobile/internal/tracing/tracingtest"
+)
+
+func TestOperation(t *testing.T) {
+	assert.Equal(t, "SELECT", operation("select id FROM public.songs"))
+	assert.Equal(t, "WITH", operation("\n\tWITH moved AS (DELETE FROM public.songs) SELECT 1"))
+	assert.Equal(t, "QUERY", operation("  "))
+}
+
+func TestPgxTracer(t *testing.T) {
+	recorder := tracingtest.NewRecorder(t)
+	tracer := PgxTracer{}
+
+	ctx, parent := Start(context.Background(), "Storage.ReadSong")
+	queryCtx := tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "SELECT * FROM public.songs WHERE id = $1"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{CommandTag: pgconn.NewCommandTag("SELECT 1")})
+
+	queryCtx = tracer.TraceQueryStart(ctx, nil, pgx.TraceQueryStartData{SQL: "DELETE FROM public.songs"})
+	tracer.TraceQueryEnd(queryCtx, nil, pgx.TraceQueryEndData{Err: errors.New("canceling statement")})
+	parent.End()
+
+	spans := recorder.GetSpans()
+	require.Len(t, spans, 3)
+
+	selectSpan := spans[0]
+	assert.Equal(t, "postgres SELECT", selectSpan.Name)
+	assert.Equal(t, trace.SpanKindClient, selectSpan.SpanKind)
+	assert.Equal(t, parent.SpanContext().SpanID(), selectSpan.Parent.SpanID())
+	assert.Contains(t, selectSpan.Attributes, semconv.DBQueryText("SELECT * FROM public.songs WHERE id = $1"))
+	assert.Contains(t, selectSpan.Attributes, semconv.DBSystemPostgreSQL)
+	assert.Equal(t, codes.Unset, selectSpan.Status.Code)
+
+	assert.Equal(t, "postgres DELETE", spans[1].Name)
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+}
+
+func TestSetupUnknownExporter(t *testing.T) {
+	_, err := Setup(context.Background(), Config{Exporter: "jaeger"})
+	assert.Error(t, err)
+
+	shutdown, err := Setup(context.Background(), Config{Exporter: ExporterNone})
+	require.NoError(t, err)
+	assert.NoError(t, shutdown(context.Background()))
+}
@@ -2,27 +2,40 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
 	"strconv"
 	"sync"
+	"syscall"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgxpool"
-	"github.com/joho/godotenv"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"EffectiveMobile/internal/api"
+	"EffectiveMobile/internal/auth"
+	"EffectiveMobile/internal/cache"
+	"EffectiveMobile/internal/config"
+	"EffectiveMobile/internal/enrichment"
+	"EffectiveMobile/internal/health"
+	"EffectiveMobile/internal/metrics"
+	"EffectiveMobile/internal/migrate"
+	"EffectiveMobile/internal/pagetoken"
+	"EffectiveMobile/internal/ratelimit"
 	"EffectiveMobile/internal/service"
 	"EffectiveMobile/internal/storage"
+	"EffectiveMobile/internal/tracing"
+	"EffectiveMobile/schema"
 )
 
 var (
 	err      error
-	logLevel int
+	conf     config.Config
+	args     []string
 	logger   *zap.Logger
 	db       *pgxpool.Pool
 	stores   *storage.Storage
@@ -31,18 +44,14 @@ var (
 )
 
 func init() {
-	// Подключение к файлу переменных окружения
-	if err = godotenv.Load(); err != nil {
-		log.Fatal("No .env file found")
-	}
-
-	logLevel, err = strconv.Atoi(os.Getenv("LOGGER_LEVEL"))
+	// Настройки из файла, переменных окружения и флагов. Аргументы после флагов - команда
+	conf, args, err = config.Load(os.Args[1:], os.LookupEnv)
 	if err != nil {
-		log.Fatal("LOGGER_LEVEL is not set")
+		log.Fatalf("Config error:\n%v", err)
 	}
 
 	var level zapcore.Level
-	level = zapcore.Level(logLevel)
+	level = zapcore.Level(conf.Log.Level)
 	logCfg := zap.NewDevelopmentConfig()
 	logCfg.Level = zap.NewAtomicLevelAt(level)
 	logger, err = logCfg.Build()
@@ -58,38 +67,174 @@ func init() {
 func main() {
 	sugar := logger.Sugar()
 
+	if len(args) > 0 && args[0] == "config" {
+		if err = runConfigCommand(conf, args); err != nil {
+			sugar.Fatalf("Command error: %s", err.Error())
+		}
+		return
+	}
+
+	// Трассировка запросов
+	tracingConfig := tracing.Config{
+		Exporter:    conf.Tracing.Exporter,
+		ServiceName: "music-store",
+		SampleRatio: conf.Tracing.SampleRatio,
+	}
+	shutdownTracing, err := tracing.Setup(context.Background(), tracingConfig)
+	if err != nil {
+		sugar.Fatalf("Tracing setup error: %s", err.Error())
+	}
+	if tracingConfig.Exporter == "" || tracingConfig.Exporter == tracing.ExporterNone {
+		sugar.Warnf("TRACING_EXPORTER is not set, spans are not exported")
+	}
+
 	// Подключение к базе данных
-	config := storage.Config{
-		Host:     os.Getenv("POSTGRES_HOST"),
-		Port:     os.Getenv("POSTGRES_PORT"),
-		Username: os.Getenv("POSTGRES_USER"),
-		Password: os.Getenv("POSTGRES_PASSWORD"),
-		Name:     os.Getenv("POSTGRES_NAME"),
-		SSLMode:  os.Getenv("POSTGRES_SSLMODE"),
-	}
-	db, err = storage.NewPostgresDB(config)
+	db, err = storage.NewPostgresDB(storage.Config{
+		Host:            conf.Database.Host,
+		Port:            strconv.Itoa(conf.Database.Port),
+		Username:        conf.Database.User,
+		Password:        conf.Database.Password,
+		Name:            conf.Database.Name,
+		SSLMode:         conf.Database.SSLMode,
+		MaxConns:        int32(conf.Pool.MaxConns),
+		MinConns:        int32(conf.Pool.MinConns),
+		MaxConnLifetime: conf.Pool.MaxConnLifetime,
+		MaxConnIdleTime: conf.Pool.MaxConnIdleTime,
+	})
 	if err != nil {
 		sugar.Fatalf("DB connection error: %s", err.Error())
 	}
 	defer db.Close()
+	metrics.RegisterPool(db)
+
+	// Пул подключается лениво, поэтому доступность базы данных проверяется до запуска
+	pingCtx, cancelPing := context.WithTimeout(context.Background(), 5*time.Second)
+	err = db.Ping(pingCtx)
+	cancelPing()
+	if err != nil {
+		sugar.Fatalf("DB is not reachable: %s", err.Error())
+	}
+
+	// Миграции базы данных
+	migrator, err := migrate.NewMigrator(db, schema.Postgres, "postgres", sugar)
+	if err != nil {
+		sugar.Fatalf("DB migrations loading error: %s", err.Error())
+	}
+
+	if len(args) > 0 {
+		if err = runCommand(migrator, args); err != nil {
+			sugar.Fatalf("Command error: %s", err.Error())
+		}
+		return
+	}
+
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		sugar.Fatalf("DB migration error: %s", err.Error())
+	}
+	sugar.Infof("Applied %v migrations, schema version %v", applied, migrator.Latest())
+
+	// Подключение к внешнему API информации о песнях
+	var enricher *enrichment.Client
+	if conf.Enrichment.URL != "" {
+		enricher = enrichment.NewClient(enrichment.Config{
+			URL:     conf.Enrichment.URL,
+			Timeout: conf.Enrichment.Timeout,
+		}, sugar)
+	} else {
+		sugar.Warnf("ENRICHMENT_URL is not set, songs will be created without details")
+	}
+
+	// Ключ подписи токенов страниц
+	pageTokenSecret := []byte(conf.Pagination.TokenSecret)
+	if len(pageTokenSecret) == 0 {
+		sugar.Warnf("PAGE_TOKEN_SECRET is not set, page tokens will not survive a restart")
+		pageTokenSecret = make([]byte, 32)
+		if _, err = rand.Read(pageTokenSecret); err != nil {
+			sugar.Fatalf("Page token secret generation error: %s", err.Error())
+		}
+	}
 
-	stores = storage.NewStorage(db, sugar)
-	services = service.NewService(stores, sugar)
-	handlers = api.NewHandler(services, sugar)
+	// Проверка JWT токенов клиентов API
+	var verifier *auth.Verifier
+	authConfig := auth.Config{
+		Secret:   []byte(conf.Auth.JWTSecret),
+		JWKSFile: conf.Auth.JWKSFile,
+		Issuer:   conf.Auth.Issuer,
+		Audience: conf.Auth.Audience,
+	}
+	if authConfig.Enabled() {
+		verifier, err = auth.NewVerifier(authConfig)
+		if err != nil {
+			sugar.Fatalf("JWT keys loading error: %s", err.Error())
+		}
+	} else {
+		sugar.Warnf("JWT_SECRET and JWT_JWKS_FILE are not set, only API keys can access routes above the reader role")
+	}
+
+	// Ограничение частоты запросов клиентов
+	var limiter *ratelimit.Limiter
+	rateLimits := conf.RateLimits()
+	if rateLimits.Enabled() {
+		limiter = ratelimit.NewLimiter(rateLimits, ratelimit.NewMemoryStore())
+		sugar.Infof("Rate limits: reads %v, writes %v, bulk %v, API key checks %v", rateLimits.Reads, rateLimits.Writes, rateLimits.Bulk, rateLimits.Auth)
+	} else {
+		sugar.Warnf("RATE_LIMIT_READS, RATE_LIMIT_WRITES, RATE_LIMIT_BULK and RATE_LIMIT_AUTH are not set, requests are not rate limited")
+	}
+
+	stores = storage.NewStorage(db, conf.Database.QueryTimeout, storage.ImportConfig{Timeout: conf.Database.ImportTimeout, BatchSize: conf.Database.ImportBatchSize}, sugar)
+
+	// Кэш чтения песен
+	var songStore storage.SongStorage = stores
+	if conf.Cache.Size > 0 {
+		cachedStore := storage.NewCachedStorage(stores, cache.Config{Size: conf.Cache.Size, TTL: conf.Cache.TTL, LoadTimeout: conf.Database.QueryTimeout}, sugar)
+		metrics.RegisterCache(cachedStore.CacheStats)
+		songStore = cachedStore
+	} else {
+		sugar.Warnf("CACHE_SIZE is 0, song reads are not cached")
+	}
+
+	// Проверки готовности приложения
+	checker := health.NewChecker(conf.Server.ReadinessTimeout)
+	checker.Add("database", db.Ping)
+	checker.Add("migrations", migrator.Current)
+	if enricher != nil && conf.Enrichment.ReadinessCheck {
+		checker.Add("enrichment", enricher.Ping)
+	}
+
+	services = service.NewService(songStore, enricher, pagetoken.NewSigner(pageTokenSecret), sugar)
+	handlers = api.NewHandler(services, verifier, limiter, checker, sugar)
+
+	// Очистка корзины
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+
+	if conf.Trash.Retention > 0 {
+		go services.RunTrashPurge(jobsCtx, conf.Trash.Retention, conf.Trash.PurgeInterval)
+	} else {
+		sugar.Warnf("TRASH_RETENTION is 0, deleted songs will be kept forever")
+	}
 
 	// Запуск веб сервера
 	httpServerExitDone := &sync.WaitGroup{}
 	httpServerExitDone.Add(1)
-	serverAddress := fmt.Sprintf("%s:%s", os.Getenv("WEBSERVER_HOST"), os.Getenv("WEBSERVER_PORT"))
+	serverAddress := fmt.Sprintf("%s:%d", conf.Server.Host, conf.Server.Port)
 
 	srv := api.StartHttpServer(httpServerExitDone, serverAddress, handlers.InitRoutes())
 	sugar.Infof("Application started")
 
 	// Setting up signal capturing
 	stop := make(chan os.Signal, 1)
-	signal.Notify(stop, os.Interrupt)
+	signal.Notify(stop, os.Interrupt, syscall.SIGTERM)
 	<-stop
 
+	// Балансировщик перестает направлять запросы до остановки веб сервера
+	checker.Shutdown()
+	sugar.Infof("Application is not ready, shutting down in %v", conf.Server.ShutdownDelay)
+	time.Sleep(conf.Server.ShutdownDelay)
+
+	stopJobs()
+
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
@@ -98,5 +243,10 @@ func main() {
 	}
 
 	httpServerExitDone.Wait()
+
+	// Отправка оставшихся спанов
+	if err = shutdownTracing(ctx); err != nil {
+		sugar.Errorf("Tracing shutdown error: %s", err.Error())
+	}
 	sugar.Infof("Application shutdown")
 }
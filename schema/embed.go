@@ -0,0 +1,9 @@
+// Package schema встраивает SQL миграции в бинарный файл приложения
+package schema
+
+import "embed"
+
+// Postgres : миграции базы данных вида NNNNNN_name.up.sql / NNNNNN_name.down.sql
+//
+//go:embed postgres/*.sql
+var Postgres embed.FS
@@ -0,0 +1,5 @@
+drop index if exists songs__search_en__idx;
+drop index if exists songs__search_ru__idx;
+
+alter table songs drop column if exists search_en;
+alter table songs drop column if exists search_ru;
@@ -0,0 +1,14 @@
+alter table songs add column if not exists search_ru tsvector generated always as (
+    setweight(to_tsvector('russian', coalesce(song_name, '')), 'A') ||
+    setweight(to_tsvector('russian', coalesce(artist_name, '')), 'B') ||
+    setweight(to_tsvector('russian', coalesce(song_text, '')), 'C')
+) stored;
+
+alter table songs add column if not exists search_en tsvector generated always as (
+    setweight(to_tsvector('english', coalesce(song_name, '')), 'A') ||
+    setweight(to_tsvector('english', coalesce(artist_name, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(song_text, '')), 'C')
+) stored;
+
+create index if not exists songs__search_ru__idx on songs using gin (search_ru);
+create index if not exists songs__search_en__idx on songs using gin (search_en);
@@ -0,0 +1 @@
+alter table songs drop column if exists version;
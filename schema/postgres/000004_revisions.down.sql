@@ -0,0 +1,3 @@
+drop trigger if exists songs__revision__trg on songs;
+drop function if exists songs_revision();
+drop table if exists song_revisions;
@@ -0,0 +1,50 @@
+create table if not exists song_revisions (
+    song_id uuid not null,
+    revision integer not null,
+    operation varchar(10) not null,
+    song_name varchar(255) not null,
+    artist_name varchar(255) not null,
+    release_date date,
+    song_text text,
+    link varchar(255),
+    version integer not null,
+    request_id varchar(255),
+    actor varchar(255),
+    created_at timestamp not null default (now() at time zone 'utc'),
+    primary key (song_id, revision)
+);
+
+-- Текущее состояние существующих песен становится их первой ревизией
+insert into song_revisions (song_id, revision, operation, song_name, artist_name, release_date, song_text, link, version, created_at)
+select id, 1, 'create', song_name, artist_name, release_date, song_text, link, version, created_at
+from songs
+on conflict do nothing;
+
+-- Request ID и автор изменения передаются приложением через app.request_id и app.actor в транзакции
+create or replace function songs_revision() returns trigger as $$
+declare
+    snapshot songs%rowtype;
+    next_revision integer;
+begin
+    if tg_op = 'DELETE' then
+        snapshot := old;
+    else
+        snapshot := new;
+    end if;
+
+    select coalesce(max(revision), 0) + 1 into next_revision
+    from song_revisions
+    where song_id = snapshot.id;
+
+    insert into song_revisions (song_id, revision, operation, song_name, artist_name, release_date, song_text, link, version, request_id, actor)
+    values (snapshot.id, next_revision, lower(tg_op), snapshot.song_name, snapshot.artist_name, snapshot.release_date, snapshot.song_text, snapshot.link, snapshot.version,
+            nullif(current_setting('app.request_id', true), ''), nullif(current_setting('app.actor', true), ''));
+
+    return null;
+end;
+$$ language plpgsql;
+
+drop trigger if exists songs__revision__trg on songs;
+create trigger songs__revision__trg
+    after insert or update or delete on songs
+    for each row execute function songs_revision();
@@ -0,0 +1,32 @@
+create or replace function songs_revision() returns trigger as $$
+declare
+    snapshot songs%rowtype;
+    next_revision integer;
+begin
+    if tg_op = 'DELETE' then
+        snapshot := old;
+    else
+        snapshot := new;
+    end if;
+
+    select coalesce(max(revision), 0) + 1 into next_revision
+    from song_revisions
+    where song_id = snapshot.id;
+
+    insert into song_revisions (song_id, revision, operation, song_name, artist_name, release_date, song_text, link, version, request_id, actor)
+    values (snapshot.id, next_revision, lower(tg_op), snapshot.song_name, snapshot.artist_name, snapshot.release_date, snapshot.song_text, snapshot.link, snapshot.version,
+            nullif(current_setting('app.request_id', true), ''), nullif(current_setting('app.actor', true), ''));
+
+    return null;
+end;
+$$ language plpgsql;
+
+-- Удаленные песни не переживают возврат к общему ограничению уникальности
+delete from songs where deleted_at is not null;
+
+drop index if exists songs__deleted_at__idx;
+drop index if exists songs__artist_song__idx;
+create unique index if not exists songs__artist_song__idx on songs (song_name, artist_name);
+alter table songs add constraint songs_song_name_artist_name_key unique (song_name, artist_name);
+
+alter table songs drop column if exists deleted_at;
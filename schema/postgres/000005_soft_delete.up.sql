@@ -0,0 +1,41 @@
+alter table songs add column if not exists deleted_at timestamp;
+
+-- Уникальность названия и исполнителя проверяется только среди неудаленных песен
+alter table songs drop constraint if exists songs_song_name_artist_name_key;
+drop index if exists songs__artist_song__idx;
+create unique index if not exists songs__artist_song__idx on songs (song_name, artist_name) where deleted_at is null;
+
+create index if not exists songs__deleted_at__idx on songs (deleted_at) where deleted_at is not null;
+
+-- Пометка удаления и восстановление записываются в журнал как delete и restore,
+-- физическое удаление из корзины - как purge
+create or replace function songs_revision() returns trigger as $$
+declare
+    snapshot songs%rowtype;
+    next_revision integer;
+    op varchar(10);
+begin
+    if tg_op = 'DELETE' then
+        snapshot := old;
+        op := 'purge';
+    else
+        snapshot := new;
+        op := lower(tg_op);
+        if tg_op = 'UPDATE' and old.deleted_at is null and new.deleted_at is not null then
+            op := 'delete';
+        elsif tg_op = 'UPDATE' and old.deleted_at is not null and new.deleted_at is null then
+            op := 'restore';
+        end if;
+    end if;
+
+    select coalesce(max(revision), 0) + 1 into next_revision
+    from song_revisions
+    where song_id = snapshot.id;
+
+    insert into song_revisions (song_id, revision, operation, song_name, artist_name, release_date, song_text, link, version, request_id, actor)
+    values (snapshot.id, next_revision, op, snapshot.song_name, snapshot.artist_name, snapshot.release_date, snapshot.song_text, snapshot.link, snapshot.version,
+            nullif(current_setting('app.request_id', true), ''), nullif(current_setting('app.actor', true), ''));
+
+    return null;
+end;
+$$ language plpgsql;
@@ -0,0 +1 @@
+alter table songs add column if not exists lyrics jsonb;
@@ -0,0 +1,12 @@
+-- Объединенные написания исполнителей и перемещенные в корзину дубликаты не восстанавливаются
+drop trigger if exists artists__rename__trg on artists;
+drop function if exists artists_rename();
+
+drop trigger if exists songs__artist__trg on songs;
+drop function if exists songs_artist();
+
+drop index if exists songs__artist_song__idx;
+create unique index if not exists songs__artist_song__idx on songs (song_name, artist_name) where deleted_at is null;
+
+alter table songs drop column if exists artist_id;
+drop table if exists artists;
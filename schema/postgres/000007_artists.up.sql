@@ -0,0 +1,92 @@
+create table if not exists artists (
+    id uuid primary key default gen_random_uuid(),
+    name varchar(255) not null,
+    created_at timestamp not null default (now() at time zone 'utc')
+);
+
+-- Названия исполнителей различаются без учета регистра
+create unique index if not exists artists__name__idx on artists (lower(name));
+
+-- Написания, отличающиеся регистром и пробелами по краям, считаются одним исполнителем.
+-- Названием исполнителя становится самое частое написание
+insert into artists (name)
+select distinct on (lower(spelling)) spelling
+from (
+    select btrim(artist_name) as spelling, count(*) as songs
+    from songs
+    group by btrim(artist_name)
+) spellings
+order by lower(spelling), songs desc, spelling
+on conflict do nothing;
+
+alter table songs add column if not exists artist_id uuid references artists (id);
+create index if not exists songs__artist_id__idx on songs (artist_id);
+
+-- Привязка к исполнителю не меняет песню и не записывается в журнал ревизий
+alter table songs disable trigger songs__revision__trg;
+update songs
+set artist_id = artists.id
+from artists
+where lower(btrim(songs.artist_name)) = lower(artists.name);
+alter table songs enable trigger songs__revision__trg;
+
+alter table songs alter column artist_id set not null;
+
+select set_config('app.actor', 'migration 000007_artists', true);
+
+-- Песни, ставшие дубликатами после объединения исполнителей, перемещаются в корзину,
+-- остается самая ранняя из них
+update songs
+set deleted_at = (now() at time zone 'utc'), version = version + 1
+where deleted_at is null
+  and exists (
+    select 1 from songs earlier
+    where earlier.deleted_at is null
+      and earlier.song_name = songs.song_name
+      and earlier.artist_id = songs.artist_id
+      and (earlier.created_at, earlier.id) < (songs.created_at, songs.id)
+  );
+
+update songs
+set artist_name = artists.name, version = version + 1
+from artists
+where artists.id = songs.artist_id
+  and songs.artist_name <> artists.name;
+
+drop index if exists songs__artist_song__idx;
+create unique index if not exists songs__artist_song__idx on songs (song_name, artist_id) where deleted_at is null;
+
+-- Песня ссылается на исполнителя по artist_id, artist_name хранит его текущее название для поиска,
+-- фильтров и журнала ревизий. Если приложение меняет только название, исполнитель находится по нему
+-- без учета регистра или создается
+create or replace function songs_artist() returns trigger as $$
+begin
+    if new.artist_id is null
+        or (tg_op = 'UPDATE' and new.artist_id = old.artist_id and new.artist_name <> old.artist_name) then
+        insert into artists (name) values (btrim(new.artist_name)) on conflict ((lower(name))) do nothing;
+        select id into new.artist_id from artists where lower(name) = lower(btrim(new.artist_name));
+    end if;
+
+    select name into new.artist_name from artists where id = new.artist_id;
+    return new;
+end;
+$$ language plpgsql;
+
+drop trigger if exists songs__artist__trg on songs;
+create trigger songs__artist__trg
+    before insert or update on songs
+    for each row execute function songs_artist();
+
+-- Переименование исполнителя меняет название во всех его песнях, включая удаленные
+create or replace function artists_rename() returns trigger as $$
+begin
+    update songs set version = version + 1 where artist_id = new.id;
+    return null;
+end;
+$$ language plpgsql;
+
+drop trigger if exists artists__rename__trg on artists;
+create trigger artists__rename__trg
+    after update of name on artists
+    for each row when (old.name is distinct from new.name)
+    execute function artists_rename();
@@ -0,0 +1,2 @@
+drop table if exists album_tracks;
+drop table if exists albums;
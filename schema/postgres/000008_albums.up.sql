@@ -0,0 +1,22 @@
+create table if not exists albums (
+    id uuid primary key default gen_random_uuid(),
+    title varchar(255) not null,
+    artist_id uuid not null references artists (id),
+    release_date date,
+    cover_link varchar(255),
+    created_at timestamp not null default (now() at time zone 'utc')
+);
+
+create index if not exists albums__artist_id__idx on albums (artist_id);
+
+-- Трек - песня на своей позиции в альбоме. Уникальность позиций проверяется в конце транзакции,
+-- чтобы треки можно было переставлять. Окончательно удаленная песня пропадает из альбомов
+create table if not exists album_tracks (
+    album_id uuid not null references albums (id) on delete cascade,
+    song_id uuid not null references songs (id) on delete cascade,
+    position integer not null check (position > 0),
+    primary key (album_id, song_id),
+    constraint album_tracks__position__key unique (album_id, position) deferrable initially deferred
+);
+
+create index if not exists album_tracks__song_id__idx on album_tracks (song_id);
@@ -0,0 +1,3 @@
+drop table if exists playlist_entries;
+drop function if exists playlist_entries_renumber();
+drop table if exists playlists;
@@ -0,0 +1,43 @@
+create table if not exists playlists (
+    id uuid primary key default gen_random_uuid(),
+    name varchar(255) not null,
+    description text,
+    owner varchar(255),
+    created_at timestamp not null default (now() at time zone 'utc')
+);
+
+-- Запись плейлиста - песня на своей позиции, одна песня может встречаться несколько раз.
+-- Уникальность позиций проверяется в конце транзакции, чтобы записи можно было сдвигать
+create table if not exists playlist_entries (
+    id uuid primary key default gen_random_uuid(),
+    playlist_id uuid not null references playlists (id) on delete cascade,
+    song_id uuid not null references songs (id) on delete cascade,
+    position integer not null check (position > 0),
+    constraint playlist_entries__position__key unique (playlist_id, position) deferrable initially deferred
+);
+
+create index if not exists playlist_entries__song_id__idx on playlist_entries (song_id);
+
+-- После удаления записей, в том числе вместе с окончательно удаленными песнями,
+-- позиции оставшихся записей плейлиста идут подряд
+create or replace function playlist_entries_renumber() returns trigger as $$
+begin
+    update playlist_entries
+    set position = numbered.position
+    from (
+        select id, row_number() over (partition by playlist_id order by position) as position
+        from playlist_entries
+        where playlist_id in (select playlist_id from deleted_entries)
+    ) numbered
+    where playlist_entries.id = numbered.id
+      and playlist_entries.position <> numbered.position;
+
+    return null;
+end;
+$$ language plpgsql;
+
+drop trigger if exists playlist_entries__renumber__trg on playlist_entries;
+create trigger playlist_entries__renumber__trg
+    after delete on playlist_entries
+    referencing old table as deleted_entries
+    for each statement execute function playlist_entries_renumber();
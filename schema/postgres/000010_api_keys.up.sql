@@ -0,0 +1,15 @@
+-- Ключи API сервисных клиентов. Хранится только SHA-256 ключа, сам ключ выдается один раз.
+-- scopes - роли ключа, expires_at null - бессрочный ключ, revoked_at - время отзыва
+create table if not exists api_keys (
+    id uuid primary key default gen_random_uuid(),
+    name varchar(255) not null,
+    prefix varchar(16) not null,
+    key_hash char(64) not null,
+    scopes text[] not null,
+    expires_at timestamp,
+    created_by varchar(255),
+    created_at timestamp not null default (now() at time zone 'utc'),
+    last_used_at timestamp,
+    revoked_at timestamp,
+    constraint api_keys__key_hash__key unique (key_hash)
+);
@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+const albumsUrl = "http://localhost:8080/api/albums"
+
+func TestAlbums(t *testing.T) {
+	t.Log("Album with ordered tracks")
+	// Создаем тестовые данные
+	name := fmt.Sprintf("Radiohead %v", time.Now().UnixNano())
+	statusCode, artist, err := SendArtist(t, http.MethodPost, artistsUrl, `{"name": "`+name+`"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, first, err := CreateSong(t, models.SongRequest{Name: "Airbag", Artist: name})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+	statusCode, second, err := CreateSong(t, models.SongRequest{Name: "Paranoid Android", Artist: name})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, album, err := SendAlbum(t, http.MethodPost, albumsUrl,
+		fmt.Sprintf(`{"title": "OK Computer", "artistId": %q, "releaseDate": "21.05.1997", "tracks": [%q, %q]}`, artist.ID, first.ID, second.ID))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, name, album.Artist)
+	require.Len(t, album.Tracks, 2)
+	assert.Equal(t, first.ID, album.Tracks[0].Song.ID)
+	assert.Equal(t, 2, album.Tracks[1].Position)
+
+	// Перестановка треков
+	statusCode, album, err = SendAlbum(t, http.MethodPut, albumsUrl+"/"+album.ID+"/tracks", fmt.Sprintf(`{"tracks": [%q, %q]}`, second.ID, first.ID))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, album.Tracks, 2)
+	assert.Equal(t, second.ID, album.Tracks[0].Song.ID)
+	assert.Equal(t, first.ID, album.Tracks[1].Song.ID)
+
+	// Песня уже есть в альбоме
+	statusCode, _, err = SendAlbum(t, http.MethodPost, albumsUrl+"/"+album.ID+"/tracks", fmt.Sprintf(`{"songId": %q}`, first.ID))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	// Фильтр списка песен по альбому
+	statusCode, songs, err := GetSongsPage(t, url.Values{"album": {album.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Len(t, songs.Songs, 2)
+
+	// Удаленная песня пропадает из треков, позиции остаются подряд
+	statusCode, err = DeleteSong(t, second)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, album, err = SendAlbum(t, http.MethodGet, albumsUrl+"/"+album.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, album.Tracks, 1)
+	assert.Equal(t, first.ID, album.Tracks[0].Song.ID)
+	assert.Equal(t, 1, album.Tracks[0].Position)
+
+	// Удаляем тестовые данные
+	statusCode, _, err = SendAlbum(t, http.MethodDelete, albumsUrl+"/"+album.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, _, err = SendAlbum(t, http.MethodGet, albumsUrl+"/"+album.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	statusCode, err = DeleteSong(t, first)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
@@ -0,0 +1,78 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+const keysUrl = "http://localhost:8080/api/keys"
+
+func TestAPIKeys(t *testing.T) {
+	t.Log("API key issue, use, rotation and revocation")
+
+	// Клиент без токена администратора из TestMain: токен и ключ вместе не принимаются
+	client := &http.Client{}
+	send := func(method string, requestUrl string, key string) int {
+		request, err := http.NewRequest(method, requestUrl, nil)
+		require.NoError(t, err)
+		request.Header.Set("X-API-Key", key)
+		response, err := client.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+
+	statusCode, issued, err := SendAPIKey(t, http.MethodPost, keysUrl, `{"name": "nightly import", "scopes": ["editor"]}`)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+	assert.NotEmpty(t, issued.Key)
+	assert.Equal(t, issued.Key[:len(issued.Prefix)], issued.Prefix)
+	assert.Nil(t, issued.LastUsedAt)
+
+	statusCode, _, err = SendAPIKey(t, http.MethodPost, keysUrl, `{"name": "job", "scopes": ["owner"]}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// Ключ с ролью editor дает право на изменения
+	assert.Equal(t, http.StatusNotFound, send(http.MethodDelete, baseUrl+"/00000000-0000-0000-0000-000000000000", issued.Key))
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, baseUrl+"s", "msk_unknown"))
+
+	// В списке ключ показан без секрета и с временем использования
+	response, err := http.Get(keysUrl + "?limit=100")
+	require.NoError(t, err)
+	var list models.APIKeysListResponse
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&list))
+	response.Body.Close()
+	var listed *models.APIKey
+	for i := range list.Keys {
+		if list.Keys[i].ID == issued.ID {
+			listed = &list.Keys[i]
+		}
+	}
+	require.NotNil(t, listed)
+	assert.NotNil(t, listed.LastUsedAt)
+
+	// После ротации прежний ключ не действует
+	statusCode, rotated, err := SendAPIKey(t, http.MethodPost, keysUrl+"/"+issued.ID+"/rotate", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEqual(t, issued.Key, rotated.Key)
+	assert.Equal(t, []string{"editor"}, rotated.Scopes)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, baseUrl+"s", issued.Key))
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, baseUrl+"s", rotated.Key))
+
+	// Отозванный ключ не действует и не ротируется
+	statusCode, _, err = SendAPIKey(t, http.MethodDelete, keysUrl+"/"+issued.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, baseUrl+"s", rotated.Key))
+	statusCode, _, err = SendAPIKey(t, http.MethodPost, keysUrl+"/"+issued.ID+"/rotate", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
@@ -0,0 +1,86 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+const artistsUrl = "http://localhost:8080/api/artists"
+
+func TestArtists(t *testing.T) {
+	t.Log("Artists are shared by songs regardless of case")
+	// Создаем тестовые данные. Исполнитель остается в базе из-за песни в корзине,
+	// поэтому название уникально для каждого запуска
+	name := fmt.Sprintf("Placebo %v", time.Now().UnixNano())
+	statusCode, artist, err := SendArtist(t, http.MethodPost, artistsUrl, `{"name": "`+name+`"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, _, err = SendArtist(t, http.MethodPost, artistsUrl, `{"name": " `+strings.ToUpper(name)+` "}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	statusCode, song, err := CreateSong(t, models.SongRequest{Name: "Every You Every Me", Artist: strings.ToLower(name) + " "})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	// Песня получает название исполнителя
+	statusCode, detail, err := GetSongByID(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, name, detail.Artist)
+
+	statusCode, songs, err := GetArtistSongs(t, artist)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, songs.Songs, 1)
+	assert.Equal(t, song.ID, songs.Songs[0].ID)
+
+	// Переименование исполнителя видно в песне
+	statusCode, renamed, err := SendArtist(t, http.MethodPut, artistsUrl+"/"+artist.ID, `{"name": "`+name+` UK"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, name+" UK", renamed.Name)
+	assert.Equal(t, 1, renamed.SongsCount)
+
+	statusCode, detail, err = GetSongByID(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, name+" UK", detail.Artist)
+
+	// Исполнителя с песнями удалить нельзя
+	statusCode, _, err = SendArtist(t, http.MethodDelete, artistsUrl+"/"+artist.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	// Удаляем тестовые данные
+	statusCode, err = DeleteSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
+
+func TestDeleteArtist(t *testing.T) {
+	t.Log("Delete an artist without songs")
+	statusCode, artist, err := SendArtist(t, http.MethodPost, artistsUrl, fmt.Sprintf(`{"name": "Mogwai %v"}`, time.Now().UnixNano()))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, _, err = SendArtist(t, http.MethodDelete, artistsUrl+"/"+artist.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, _, err = SendArtist(t, http.MethodGet, artistsUrl+"/"+artist.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	statusCode, _, err = GetArtistSongs(t, artist)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
@@ -0,0 +1,45 @@
+package tests
+
+import (
+	"net/http"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthorization(t *testing.T) {
+	secret := os.Getenv("JWT_SECRET")
+	t.Log("Role checks for writes and bulk routes")
+
+	// Клиент без токена администратора из TestMain
+	client := &http.Client{}
+	send := func(method string, requestUrl string, token string) int {
+		request, err := http.NewRequest(method, requestUrl, nil)
+		require.NoError(t, err)
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		response, err := client.Do(request)
+		require.NoError(t, err)
+		defer response.Body.Close()
+		return response.StatusCode
+	}
+
+	reader, err := adminToken(secret, "reader")
+	require.NoError(t, err)
+	editor, err := adminToken(secret, "editor")
+	require.NoError(t, err)
+
+	// Чтение публичное
+	assert.Equal(t, http.StatusOK, send(http.MethodGet, baseUrl+"s", ""))
+	// Изменения требуют роли editor
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodDelete, baseUrl+"/00000000-0000-0000-0000-000000000000", ""))
+	assert.Equal(t, http.StatusForbidden, send(http.MethodDelete, baseUrl+"/00000000-0000-0000-0000-000000000000", reader))
+	assert.Equal(t, http.StatusNotFound, send(http.MethodDelete, baseUrl+"/00000000-0000-0000-0000-000000000000", editor))
+	// Массовые операции требуют роли admin
+	assert.Equal(t, http.StatusForbidden, send(http.MethodGet, baseUrl+"s/export", editor))
+	// Неверный токен отклоняется и на публичных маршрутах
+	assert.Equal(t, http.StatusUnauthorized, send(http.MethodGet, baseUrl+"s", "not.a.token"))
+}
@@ -0,0 +1,66 @@
+package tests
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+const cacheUrl = "http://localhost:8080/api/cache"
+
+func getCacheStats(t *testing.T) models.CacheStatsResponse {
+	response, err := http.Get(cacheUrl)
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var result models.CacheStatsResponse
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&result))
+	return result
+}
+
+func TestSongCache(t *testing.T) {
+	if !getCacheStats(t).Enabled {
+		t.Skip("CACHE_SIZE is 0, song reads are not cached")
+	}
+	t.Log("Cached song reads see updates at once")
+
+	statusCode, created, err := CreateSong(t, models.SongRequest{Name: "Plug In Baby", Artist: fmt.Sprintf("Muse %v", time.Now().UnixNano())})
+	require.NoError(t, err)
+	require.Equal(t, http.StatusCreated, statusCode)
+
+	before := getCacheStats(t).Caches[0]
+	for i := 0; i < 3; i++ {
+		statusCode, _, err = GetSongByID(t, created)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+	}
+	after := getCacheStats(t).Caches[0]
+	assert.Equal(t, "songs", after.Name)
+	assert.GreaterOrEqual(t, after.Hits-before.Hits, uint64(2))
+
+	// Изменение удаляет песню из кэша
+	statusCode, song, err := GetSongByID(t, created)
+	require.NoError(t, err)
+	song.Link = "https://example.com/plug-in-baby"
+	statusCode, _, err = UpdateSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	statusCode, song, err = GetSongByID(t, created)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/plug-in-baby", song.Link)
+
+	statusCode, err = DeleteSong(t, created)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	statusCode, _, err = GetSongByID(t, created)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
@@ -51,3 +51,26 @@ func TestCreateSongWithEmptyName(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusBadRequest, statusCode)
 }
+
+func TestCreateSongDuplicate(t *testing.T) {
+	t.Log("Create duplicate song")
+
+	testSong := models.SongRequest{
+		Name:   "Uprising",
+		Artist: "Muse",
+	}
+
+	statusCode, song, err := CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	// Повторное создание песни с тем же названием и группой
+	statusCode, _, err = CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	// Удаляем тестовые данные
+	statusCode, err = DeleteSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
@@ -0,0 +1,67 @@
+package tests
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestExportSongs(t *testing.T) {
+	t.Log("Export songs")
+	// Создаем тестовые данные
+	testSongs := []models.SongRequest{
+		{Name: "Plug In Baby", Artist: "Export Test"},
+		{Name: "New Born", Artist: "Export Test"},
+	}
+	var songs []models.SongResponse
+	for _, testSong := range testSongs {
+		statusCode, song, err := CreateSong(t, testSong)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+		songs = append(songs, song)
+	}
+
+	query := url.Values{"group": {"eq:Export Test"}, "sort_by": {"song"}, "sort_order": {"asc"}}
+
+	statusCode, contentType, body, err := ExportSongs(t, query)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "application/x-ndjson", contentType)
+
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	require.Len(t, lines, 2)
+	var first models.Song
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "New Born", first.Name)
+
+	query.Set("format", "csv")
+	statusCode, _, body, err = ExportSongs(t, query)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	records, err := csv.NewReader(strings.NewReader(body)).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, records, 3)
+	assert.Equal(t, []string{"id", "song", "group", "releaseDate", "text", "link"}, records[0])
+	assert.Equal(t, "Plug In Baby", records[2][1])
+
+	query.Set("format", "xml")
+	statusCode, _, _, err = ExportSongs(t, query)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// Удаляем тестовые данные
+	for _, song := range songs {
+		statusCode, err = DeleteSong(t, song)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"testing"
 
 	"EffectiveMobile/internal/models"
@@ -54,7 +55,7 @@ func CreateSong(t *testing.T, testSong models.SongRequest) (int, models.SongResp
 func DeleteSong(t *testing.T, song models.SongResponse) (int, error) {
 	t.Log("Calling the API to delete a song")
 
-	client := &http.Client{}
+	client := http.DefaultClient
 	deleteUrl := baseUrl + "/" + song.ID
 	t.Log("Sending request to ", deleteUrl)
 	deleteRequest, err := http.NewRequest(http.MethodDelete, deleteUrl, nil)
@@ -126,7 +127,7 @@ func UpdateSong(t *testing.T, testSong models.Song) (statusCode int, result mode
 		return -1, result, err
 	}
 
-	client := &http.Client{}
+	client := http.DefaultClient
 	t.Log("Sending request to ", baseUrl)
 	t.Log("Sending request body", string(body))
 	updateResponse, err = client.Do(updateRequest)
@@ -209,7 +210,7 @@ func GetCouplet(t *testing.T, song models.SongResponse, coupletId string) (statu
 	getUrl := baseUrl + "/" + song.ID + "/couplet" + "?couplet_id=" + coupletId
 
 	t.Log("Sending request to ", getUrl)
-	client := &http.Client{}
+	client := http.DefaultClient
 	getRequest, err = http.NewRequest(http.MethodGet, getUrl, nil)
 	if err != nil {
 		t.Logf("Error creating get request: %v", err)
@@ -270,3 +271,542 @@ func GetSongsList(t *testing.T) (statusCode int, songsList models.SongsListRespo
 
 	return response.StatusCode, songsList, err
 }
+
+func GetSongsPage(t *testing.T, query url.Values) (statusCode int, songsList models.SongsListResponse, err error) {
+	t.Log("Calling the API to get a songs list page")
+
+	var response *http.Response
+
+	getUrl := baseUrl + "s/?" + query.Encode()
+	t.Log("Sending request to ", getUrl)
+
+	response, err = http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error getting songs page: %v", err)
+		return -1, songsList, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, songsList, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &songsList); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, songsList, err
+	}
+
+	return response.StatusCode, songsList, err
+}
+
+func SearchSongs(t *testing.T, query url.Values) (statusCode int, result models.SongsSearchResponse, err error) {
+	t.Log("Calling the API to search songs")
+
+	var response *http.Response
+
+	getUrl := baseUrl + "s/search?" + query.Encode()
+	t.Log("Sending request to ", getUrl)
+
+	response, err = http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error searching songs: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func PatchSong(t *testing.T, song models.SongResponse, patch string) (statusCode int, result models.Song, err error) {
+	t.Log("Calling the API to patch a song")
+
+	patchUrl := baseUrl + "/" + song.ID
+	t.Log("Sending request to ", patchUrl)
+	t.Log("Sending request body", patch)
+
+	patchRequest, err := http.NewRequest(http.MethodPatch, patchUrl, bytes.NewBufferString(patch))
+	if err != nil {
+		t.Logf("Error creating patch request: %v", err)
+		return -1, result, err
+	}
+	patchRequest.Header.Set("Content-Type", "application/merge-patch+json")
+
+	patchResponse, err := http.DefaultClient.Do(patchRequest)
+	if err != nil {
+		t.Logf("Error patching song: %v", err)
+		return -1, result, err
+	}
+	defer patchResponse.Body.Close()
+
+	responseData, err := io.ReadAll(patchResponse.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return patchResponse.StatusCode, result, err
+}
+
+func SendWithHeaders(t *testing.T, method string, requestUrl string, body string, headers map[string]string) (statusCode int, etag string, err error) {
+	t.Logf("Calling the API: %v %v", method, requestUrl)
+
+	request, err := http.NewRequest(method, requestUrl, bytes.NewBufferString(body))
+	if err != nil {
+		t.Logf("Error creating request: %v", err)
+		return -1, "", err
+	}
+	for key, value := range headers {
+		request.Header.Set(key, value)
+	}
+	t.Log("Sending request headers", headers)
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Logf("Error sending request: %v", err)
+		return -1, "", err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, "", err
+	}
+
+	t.Log("Response: ", string(responseData))
+	t.Log("Status code: ", response.StatusCode)
+
+	return response.StatusCode, response.Header.Get("ETag"), err
+}
+
+func GetSongRevisions(t *testing.T, song models.SongResponse) (statusCode int, result models.SongRevisionsResponse, err error) {
+	t.Log("Calling the API to get song revisions")
+
+	getUrl := baseUrl + "/" + song.ID + "/revisions"
+	t.Log("Sending request to ", getUrl)
+
+	response, err := http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error getting song revisions: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func DiffSongRevisions(t *testing.T, song models.SongResponse, from string, to string) (statusCode int, result models.SongRevisionDiff, err error) {
+	t.Log("Calling the API to diff song revisions")
+
+	getUrl := baseUrl + "/" + song.ID + "/revisions/diff?from=" + from + "&to=" + to
+	t.Log("Sending request to ", getUrl)
+
+	response, err := http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error diffing song revisions: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func RevertSong(t *testing.T, song models.SongResponse, revision string) (statusCode int, result models.Song, err error) {
+	t.Log("Calling the API to revert a song")
+
+	postUrl := baseUrl + "/" + song.ID + "/revisions/" + revision + "/revert"
+	t.Log("Sending request to ", postUrl)
+
+	response, err := http.Post(postUrl, "application/json", nil)
+	if err != nil {
+		t.Logf("Error reverting song: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func GetTrash(t *testing.T, query url.Values) (statusCode int, result models.TrashResponse, err error) {
+	t.Log("Calling the API to get the trash")
+
+	getUrl := "http://localhost:8080/api/trash?" + query.Encode()
+	t.Log("Sending request to ", getUrl)
+
+	response, err := http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error getting trash: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func RestoreSong(t *testing.T, song models.SongResponse) (statusCode int, result models.Song, err error) {
+	t.Log("Calling the API to restore a song")
+
+	postUrl := baseUrl + "/" + song.ID + "/restore"
+	t.Log("Sending request to ", postUrl)
+
+	response, err := http.Post(postUrl, "application/json", nil)
+	if err != nil {
+		t.Logf("Error restoring song: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func ImportSongs(t *testing.T, contentType string, body string, dryRun bool) (statusCode int, result models.ImportReport, err error) {
+	t.Log("Calling the API to import songs")
+
+	postUrl := baseUrl + "s/import"
+	if dryRun {
+		postUrl += "?dry_run=true"
+	}
+	t.Log("Sending request to ", postUrl)
+	t.Log("Sending request body", body)
+
+	response, err := http.Post(postUrl, contentType, bytes.NewBufferString(body))
+	if err != nil {
+		t.Logf("Error importing songs: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func ExportSongs(t *testing.T, query url.Values) (statusCode int, contentType string, body string, err error) {
+	t.Log("Calling the API to export songs")
+
+	getUrl := baseUrl + "s/export?" + query.Encode()
+	t.Log("Sending request to ", getUrl)
+
+	response, err := http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error exporting songs: %v", err)
+		return -1, "", "", err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, "", "", err
+	}
+
+	return response.StatusCode, response.Header.Get("Content-Type"), string(responseData), err
+}
+
+func GetSongLyrics(t *testing.T, song models.SongResponse) (statusCode int, result models.SongLyricsResponse, err error) {
+	t.Log("Calling the API to get song lyrics")
+
+	getUrl := "http://localhost:8080/api/song/" + song.ID + "/lyrics"
+	t.Log("Sending request to ", getUrl)
+
+	response, err := http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error getting song lyrics: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func SendArtist(t *testing.T, method string, requestUrl string, body string) (statusCode int, result models.Artist, err error) {
+	t.Logf("Calling the API: %v %v", method, requestUrl)
+
+	request, err := http.NewRequest(method, requestUrl, bytes.NewBufferString(body))
+	if err != nil {
+		t.Logf("Error creating request: %v", err)
+		return -1, result, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Logf("Error sending request: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func GetArtistSongs(t *testing.T, artist models.Artist) (statusCode int, result models.SongsListResponse, err error) {
+	t.Log("Calling the API to get artist songs")
+
+	getUrl := "http://localhost:8080/api/artists/" + artist.ID + "/songs"
+	t.Log("Sending request to ", getUrl)
+
+	response, err := http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error getting artist songs: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func SendAlbum(t *testing.T, method string, requestUrl string, body string) (statusCode int, result models.Album, err error) {
+	t.Logf("Calling the API: %v %v", method, requestUrl)
+
+	request, err := http.NewRequest(method, requestUrl, bytes.NewBufferString(body))
+	if err != nil {
+		t.Logf("Error creating request: %v", err)
+		return -1, result, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Logf("Error sending request: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func SendPlaylist(t *testing.T, method string, requestUrl string, body string) (statusCode int, result models.Playlist, err error) {
+	t.Logf("Calling the API: %v %v", method, requestUrl)
+
+	request, err := http.NewRequest(method, requestUrl, bytes.NewBufferString(body))
+	if err != nil {
+		t.Logf("Error creating request: %v", err)
+		return -1, result, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Logf("Error sending request: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
+
+func ExportPlaylist(t *testing.T, playlist models.Playlist, format string) (statusCode int, contentType string, body string, err error) {
+	t.Log("Calling the API to export playlist")
+
+	getUrl := "http://localhost:8080/api/playlists/" + playlist.ID + "/export?format=" + format
+	t.Log("Sending request to ", getUrl)
+
+	response, err := http.Get(getUrl)
+	if err != nil {
+		t.Logf("Error exporting playlist: %v", err)
+		return -1, "", "", err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, "", "", err
+	}
+
+	return response.StatusCode, response.Header.Get("Content-Type"), string(responseData), err
+}
+
+func SendAPIKey(t *testing.T, method string, requestUrl string, body string) (statusCode int, result models.APIKeyIssued, err error) {
+	t.Logf("Calling the API: %v %v", method, requestUrl)
+
+	request, err := http.NewRequest(method, requestUrl, bytes.NewBufferString(body))
+	if err != nil {
+		t.Logf("Error creating request: %v", err)
+		return -1, result, err
+	}
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Logf("Error sending request: %v", err)
+		return -1, result, err
+	}
+	defer response.Body.Close()
+
+	responseData, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Logf("Error reading response body: %v", err)
+		return -1, result, err
+	}
+
+	t.Log("Response: ", string(responseData))
+
+	if err = json.Unmarshal(responseData, &result); err != nil {
+		t.Logf("Error unmarshalling response body: %v", err)
+		return -1, result, err
+	}
+
+	return response.StatusCode, result, err
+}
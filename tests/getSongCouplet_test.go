@@ -40,17 +40,45 @@ func TestGetSongCouplet(t *testing.T) {
 	assert.Equal(t, testUpdateSong, detailSong)
 
 	// Проверяем получение куплета
-	couplets := strings.Split(testUpdateSong.Text, "\\n\\n")
+	// Переводы строк в куплете нормализуются
+	couplets := strings.Split(strings.ReplaceAll(testUpdateSong.Text, "\\n", "\n"), "\n\n")
 	response := models.SongVerseResponse{}
-	statusCode, response, err = GetCouplet(t, song, "0")
+	statusCode, response, err = GetCouplet(t, song, "1")
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, statusCode)
 	assert.Equal(t, couplets[0], response.Couplet)
+	assert.Equal(t, 1, response.CoupletId)
+	assert.Equal(t, 2, response.TotalCouplets)
 
-	statusCode, response, err = GetCouplet(t, song, "1")
+	statusCode, response, err = GetCouplet(t, song, "2")
 	require.NoError(t, err)
 	assert.Equal(t, couplets[1], response.Couplet)
 
+	// Диапазоны и списки номеров
+	statusCode, response, err = GetCouplet(t, song, "1-2")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, response.Couplets, 2)
+	assert.Equal(t, 2, response.Couplets[1].Number)
+	assert.Equal(t, couplets[1], response.Couplets[1].Text)
+
+	statusCode, response, err = GetCouplet(t, song, "2,1")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, couplets[1]+"\n\n"+couplets[0], response.Couplet)
+
+	// Номер за пределами текста песни
+	statusCode, _, err = GetCouplet(t, song, "2-3")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// Некорректный номер
+	for _, coupletId := range []string{"0", "x", "3-2"} {
+		statusCode, _, err = GetCouplet(t, song, coupletId)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+	}
+
 	// Удаляем тестовые данные
 	statusCode, err = DeleteSong(t, song)
 	require.NoError(t, err)
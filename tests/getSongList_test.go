@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"net/http"
+	"net/url"
 	"testing"
 )
 
@@ -49,3 +50,58 @@ func TestGetSongList(t *testing.T) {
 	}
 
 }
+
+func TestGetSongListPageToken(t *testing.T) {
+	t.Log("Get song list by page token")
+	var toDelete []models.SongResponse
+
+	for i := 0; i < 5; i++ {
+		testSong := models.SongRequest{
+			Name:   fmt.Sprintf("Page song %v", i),
+			Artist: "Page Token Group",
+		}
+
+		statusCode, song, err := CreateSong(t, testSong)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+
+		toDelete = append(toDelete, song)
+	}
+
+	// Листаем список по две песни, пока есть следующая страница
+	query := url.Values{}
+	query.Set("group", "eq:Page Token Group")
+	query.Set("sort_by", "song")
+	query.Set("sort_order", "asc")
+	query.Set("limit", "2")
+
+	var names []string
+	for {
+		statusCode, songsList, err := GetSongsPage(t, query)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, statusCode)
+
+		for _, song := range songsList.Songs {
+			names = append(names, song.Name)
+		}
+		if songsList.NextPageToken == "" {
+			break
+		}
+		query.Set("page_token", songsList.NextPageToken)
+	}
+
+	assert.Equal(t, []string{"Page song 0", "Page song 1", "Page song 2", "Page song 3", "Page song 4"}, names)
+
+	// Токен, выданный для другой сортировки, не принимается
+	query.Set("sort_order", "desc")
+	statusCode, _, err := GetSongsPage(t, query)
+	require.NoError(t, err)
+	assert.NotEqual(t, http.StatusOK, statusCode)
+
+	// удаляем тестовые данные
+	for _, song := range toDelete {
+		statusCode, err := DeleteSong(t, song)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+	}
+}
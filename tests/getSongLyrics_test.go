@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestGetSongLyrics(t *testing.T) {
+	t.Log("Lyrics split into sections")
+	// Создаем тестовые данные
+	testSong := models.SongRequest{
+		Name:   "Yellow Submarine",
+		Artist: "The Beatles",
+	}
+
+	statusCode, song, err := CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, _, err = PatchSong(t, song, `{"text": "[Verse 1]\nIn the town where I was born\nLived a man who sailed to sea\n\n[Chorus]\nWe all live in a yellow submarine\nYellow submarine, yellow submarine\n\n[Verse 2]\nSo we sailed up to the sun\n\n[Chorus]"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, lyrics, err := GetSongLyrics(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, song.ID, lyrics.ID)
+	require.Len(t, lyrics.Sections, 4)
+	assert.Equal(t, "verse", lyrics.Sections[0].Type)
+	assert.Equal(t, "chorus", lyrics.Sections[1].Type)
+	assert.Equal(t, 2, lyrics.Sections[2].Index)
+	// Пустая метка повторяет припев
+	assert.Equal(t, lyrics.Sections[1].Lines, lyrics.Sections[3].Lines)
+	assert.Equal(t, 2, lyrics.Sections[3].Index)
+
+	statusCode, err = DeleteSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, _, err = GetSongLyrics(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
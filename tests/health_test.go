@@ -0,0 +1,32 @@
+package tests
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestHealth(t *testing.T) {
+	t.Log("Liveness and readiness of the running application")
+
+	response, err := http.Get("http://localhost:8080/healthz")
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+
+	response, err = http.Get("http://localhost:8080/readyz")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+
+	var result models.ReadinessResponse
+	require.NoError(t, json.NewDecoder(response.Body).Decode(&result))
+	assert.Equal(t, "ready", result.Status)
+	assert.Equal(t, "up", result.Dependencies["database"].Status)
+	assert.Equal(t, "up", result.Dependencies["migrations"].Status)
+}
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestImportSongs(t *testing.T) {
+	t.Log("Import songs")
+	body := "song,group,releaseDate,text\n" +
+		"Knights of Cydonia,Muse,27.11.2006,Come ride with me\n" +
+		"Map of the Problematique,Muse,,\n" +
+		"Knights of Cydonia,Muse,,\n" +
+		"Assassin,,,\n"
+
+	// Проверка без сохранения
+	statusCode, report, err := ImportSongs(t, "text/csv", body, true)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.True(t, report.DryRun)
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 1, report.Duplicate)
+	assert.Equal(t, 1, report.Invalid)
+	assert.Empty(t, report.Rows[0].ID)
+
+	statusCode, report, err = ImportSongs(t, "text/csv", body, false)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, report.Rows, 4)
+	assert.Equal(t, models.ImportCreated, report.Rows[0].Status)
+	assert.Equal(t, models.ImportCreated, report.Rows[1].Status)
+	assert.Equal(t, models.ImportDuplicate, report.Rows[2].Status)
+	assert.Equal(t, models.ImportInvalid, report.Rows[3].Status)
+
+	statusCode, song, err := GetSongByID(t, models.SongResponse{ID: report.Rows[0].ID})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "Come ride with me", song.Text)
+
+	// Повторный импорт не создает дубликатов
+	var again models.ImportReport
+	statusCode, again, err = ImportSongs(t, "application/x-ndjson", `{"song": "Knights of Cydonia", "group": "Muse"}`, false)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, 1, again.Duplicate)
+
+	// Удаляем тестовые данные
+	for _, row := range report.Rows[:2] {
+		statusCode, err = DeleteSong(t, models.SongResponse{ID: row.ID})
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+	}
+}
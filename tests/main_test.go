@@ -0,0 +1,48 @@
+package tests
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// bearerTransport : добавляет токен администратора ко всем запросам тестов без своего токена или ключа API
+type bearerTransport struct {
+	token string
+}
+
+func (t bearerTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	if request.Header.Get("Authorization") == "" && request.Header.Get("X-API-Key") == "" {
+		request = request.Clone(request.Context())
+		request.Header.Set("Authorization", "Bearer "+t.token)
+	}
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+// adminToken : Токен с ролью admin, подписанный тем же JWT_SECRET, что и у приложения
+func adminToken(secret string, roles ...string) (string, error) {
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":   "integration-tests",
+		"roles": roles,
+		"exp":   time.Now().Add(time.Hour).Unix(),
+	}).SignedString([]byte(secret))
+}
+
+func TestMain(m *testing.M) {
+	// Без токена приложение отклоняет изменения, поэтому тестам нужен тот же JWT_SECRET
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		log.Fatalf("JWT_SECRET is not set, use the secret the application was started with")
+	}
+	token, err := adminToken(secret, "admin")
+	if err != nil {
+		log.Fatalf("Error signing test token: %v", err)
+	}
+	http.DefaultClient.Transport = bearerTransport{token: token}
+
+	os.Exit(m.Run())
+}
@@ -0,0 +1,31 @@
+package tests
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	t.Log("Prometheus metrics by route pattern")
+
+	response, err := http.Get(baseUrl + "/00000000-0000-0000-0000-000000000000")
+	require.NoError(t, err)
+	response.Body.Close()
+	assert.Equal(t, http.StatusNotFound, response.StatusCode)
+
+	response, err = http.Get("http://localhost:8080/metrics")
+	require.NoError(t, err)
+	defer response.Body.Close()
+	require.Equal(t, http.StatusOK, response.StatusCode)
+	body, err := io.ReadAll(response.Body)
+	require.NoError(t, err)
+
+	assert.Contains(t, string(body), `music_store_http_requests_total{method="GET",route="/api/song/{id}",status="404"}`)
+	assert.NotContains(t, string(body), "00000000-0000-0000-0000-000000000000")
+	assert.Contains(t, string(body), "music_store_db_pool_acquired_connections")
+	assert.Contains(t, string(body), `music_store_storage_query_duration_seconds_count{method="ReadSong"}`)
+}
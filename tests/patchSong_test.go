@@ -0,0 +1,62 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestPatchSong(t *testing.T) {
+	t.Log("Patch song")
+	// Создаем тестовые данные
+	testSong := models.SongRequest{
+		Name:   "Hysteria",
+		Artist: "Muse",
+	}
+
+	statusCode, song, err := CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	testUpdateSong := models.Song{
+		ID:      song.ID,
+		Name:    testSong.Name,
+		Artist:  testSong.Artist,
+		Release: "01.12.2003",
+		Text:    "It's bugging me\nGrating me",
+		Link:    "https://www.youtube.com/watch?v=old",
+	}
+
+	statusCode, _, err = UpdateSong(t, testUpdateSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// Меняем только ссылку, остальные поля не трогаем
+	var result models.Song
+	statusCode, result, err = PatchSong(t, song, `{"link": "https://www.youtube.com/watch?v=3dm_5qWWDV8"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	testUpdateSong.Link = "https://www.youtube.com/watch?v=3dm_5qWWDV8"
+	assert.Equal(t, testUpdateSong, result)
+
+	// Явный null очищает поле
+	statusCode, result, err = PatchSong(t, song, `{"text": null}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	testUpdateSong.Text = ""
+	assert.Equal(t, testUpdateSong, result)
+
+	// Дата проверяется так же, как в PUT
+	statusCode, _, err = PatchSong(t, song, `{"releaseDate": "2003-12-01"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+
+	// Удаляем тестовые данные
+	statusCode, err = DeleteSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
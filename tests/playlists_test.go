@@ -0,0 +1,105 @@
+package tests
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+const playlistsUrl = "http://localhost:8080/api/playlists"
+
+func TestPlaylists(t *testing.T) {
+	t.Log("Playlist with gap-free entry positions")
+	// Создаем тестовые данные
+	artist := fmt.Sprintf("Muse %v", time.Now().UnixNano())
+	statusCode, first, err := CreateSong(t, models.SongRequest{Name: "Knights of Cydonia", Artist: artist})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+	statusCode, second, err := CreateSong(t, models.SongRequest{Name: "Map of the Problematique", Artist: artist})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, playlist, err := SendPlaylist(t, http.MethodPost, playlistsUrl, `{"name": "Road trip", "description": "Long drive"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+	assert.Equal(t, "Road trip", playlist.Name)
+	assert.Empty(t, playlist.Entries)
+	entriesUrl := playlistsUrl + "/" + playlist.ID + "/entries"
+
+	// Добавление в конец и вставка в начало, песня может повторяться
+	for _, body := range []string{
+		fmt.Sprintf(`{"songId": %q}`, first.ID),
+		fmt.Sprintf(`{"songId": %q}`, second.ID),
+		fmt.Sprintf(`{"songId": %q, "position": 1}`, second.ID),
+	} {
+		statusCode, playlist, err = SendPlaylist(t, http.MethodPost, entriesUrl, body)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusCreated, statusCode)
+	}
+	require.Len(t, playlist.Entries, 3)
+	assert.Equal(t, []string{second.ID, first.ID, second.ID},
+		[]string{playlist.Entries[0].Song.ID, playlist.Entries[1].Song.ID, playlist.Entries[2].Song.ID})
+	assert.Equal(t, "Knights of Cydonia", playlist.Entries[1].Song.Name)
+
+	// Позиция за концом плейлиста
+	statusCode, _, err = SendPlaylist(t, http.MethodPost, entriesUrl, fmt.Sprintf(`{"songId": %q, "position": 5}`, first.ID))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnprocessableEntity, statusCode)
+
+	// Перемещение первой записи в конец
+	statusCode, playlist, err = SendPlaylist(t, http.MethodPatch, entriesUrl+"/1", `{"position": 3}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, playlist.Entries, 3)
+	assert.Equal(t, first.ID, playlist.Entries[0].Song.ID)
+	assert.Equal(t, 3, playlist.Entries[2].Position)
+
+	// После удаления записи позиции идут подряд
+	statusCode, playlist, err = SendPlaylist(t, http.MethodDelete, entriesUrl+"/1", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, playlist.Entries, 2)
+	assert.Equal(t, []int{1, 2}, []int{playlist.Entries[0].Position, playlist.Entries[1].Position})
+
+	statusCode, _, err = SendPlaylist(t, http.MethodDelete, entriesUrl+"/3", "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// Выгрузка
+	statusCode, contentType, body, err := ExportPlaylist(t, playlist, "m3u8")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "application/vnd.apple.mpegurl", contentType)
+	assert.Contains(t, body, "#PLAYLIST:Road trip\n")
+
+	statusCode, contentType, body, err = ExportPlaylist(t, playlist, "xspf")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, "application/xspf+xml", contentType)
+	assert.Contains(t, body, "<title>Road trip</title>")
+
+	statusCode, _, _, err = ExportPlaylist(t, playlist, "pls")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// Удаляем тестовые данные
+	statusCode, _, err = SendPlaylist(t, http.MethodDelete, playlistsUrl+"/"+playlist.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, _, err = SendPlaylist(t, http.MethodGet, playlistsUrl+"/"+playlist.ID, "")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	for _, song := range []models.SongResponse{first, second} {
+		statusCode, err = DeleteSong(t, song)
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, statusCode)
+	}
+}
@@ -44,3 +44,11 @@ func TestReadSong(t *testing.T) {
 	require.NoError(t, err)
 	assert.Equal(t, http.StatusOK, statusCode)
 }
+
+func TestReadSongNotFound(t *testing.T) {
+	t.Log("Read not existing song")
+
+	statusCode, _, err := GetSongByID(t, models.SongResponse{ID: "00000000-0000-0000-0000-000000000000"})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+}
@@ -0,0 +1,59 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestSearchSongs(t *testing.T) {
+	t.Log("Search songs")
+	// Создаем тестовые данные
+	testSong := models.SongRequest{
+		Name:   "Группа крови",
+		Artist: "Кино",
+	}
+
+	statusCode, song, err := CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	testUpdateSong := models.Song{
+		ID:     song.ID,
+		Name:   testSong.Name,
+		Artist: testSong.Artist,
+		Text:   "Теплое место, но улицы ждут отпечатков наших ног\nЗвездная пыль на сапогах",
+	}
+
+	statusCode, _, err = UpdateSong(t, testUpdateSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// Ищем песню по словоформе из текста
+	query := url.Values{}
+	query.Set("q", "сапог")
+	query.Set("lang", "ru")
+
+	var result models.SongsSearchResponse
+	statusCode, result, err = SearchSongs(t, query)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, result.Results, 1)
+	assert.Equal(t, song.ID, result.Results[0].ID)
+	assert.Contains(t, result.Results[0].Snippet, "<b>сапогах</b>")
+
+	// Запрос без текста поиска
+	statusCode, _, err = SearchSongs(t, url.Values{})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, statusCode)
+
+	// Удаляем тестовые данные
+	statusCode, err = DeleteSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
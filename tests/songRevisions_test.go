@@ -0,0 +1,77 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestSongRevisions(t *testing.T) {
+	t.Log("Song revisions")
+	// Создаем тестовые данные
+	testSong := models.SongRequest{
+		Name:   "Starlight",
+		Artist: "Muse",
+	}
+
+	statusCode, song, err := CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	testUpdateSong := models.Song{
+		ID:     song.ID,
+		Name:   testSong.Name,
+		Artist: testSong.Artist,
+		Text:   "Far away\nThis ship is taking me far away",
+	}
+
+	statusCode, _, err = UpdateSong(t, testUpdateSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// Портим текст песни
+	statusCode, _, err = PatchSong(t, song, `{"text": "corrupted"}`)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	var revisions models.SongRevisionsResponse
+	statusCode, revisions, err = GetSongRevisions(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, revisions.Revisions, 3)
+	assert.Equal(t, "create", revisions.Revisions[0].Operation)
+	assert.Equal(t, "update", revisions.Revisions[2].Operation)
+	assert.NotEmpty(t, revisions.Revisions[2].RequestID)
+
+	var diff models.SongRevisionDiff
+	statusCode, diff, err = DiffSongRevisions(t, song, "2", "3")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, []models.FieldChange{{Field: "text", From: testUpdateSong.Text, To: "corrupted"}}, diff.Changes)
+
+	// Возвращаем текст из второй ревизии
+	var result models.Song
+	statusCode, result, err = RevertSong(t, song, "2")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, testUpdateSong, result)
+
+	statusCode, _, err = RevertSong(t, song, "42")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	// Удаляем тестовые данные, журнал ревизий сохраняется
+	statusCode, err = DeleteSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	statusCode, revisions, err = GetSongRevisions(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.Len(t, revisions.Revisions, 5)
+	assert.Equal(t, "delete", revisions.Revisions[4].Operation)
+}
@@ -0,0 +1,56 @@
+package tests
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestSongVersion(t *testing.T) {
+	t.Log("Optimistic concurrency with ETag")
+	// Создаем тестовые данные
+	testSong := models.SongRequest{
+		Name:   "Time Is Running Out",
+		Artist: "Muse",
+	}
+
+	statusCode, song, err := CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	songUrl := baseUrl + "/" + song.ID
+
+	statusCode, etag, err := SendWithHeaders(t, http.MethodGet, songUrl, "", nil)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	require.NotEmpty(t, etag)
+
+	// Клиент с актуальной версией получает 304 без тела
+	statusCode, _, err = SendWithHeaders(t, http.MethodGet, songUrl, "", map[string]string{"If-None-Match": etag})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotModified, statusCode)
+
+	// Изменение с актуальной версией проходит и меняет ETag
+	statusCode, newEtag, err := SendWithHeaders(t, http.MethodPatch, songUrl, `{"text": "Im growing tired"}`, map[string]string{"If-Match": etag})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.NotEqual(t, etag, newEtag)
+
+	// Изменение и удаление по устаревшей версии отклоняются
+	statusCode, _, err = SendWithHeaders(t, http.MethodPatch, songUrl, `{"text": null}`, map[string]string{"If-Match": etag})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, statusCode)
+
+	statusCode, _, err = SendWithHeaders(t, http.MethodDelete, songUrl, "", map[string]string{"If-Match": etag})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusPreconditionFailed, statusCode)
+
+	// Удаляем тестовые данные
+	statusCode, _, err = SendWithHeaders(t, http.MethodDelete, songUrl, "", map[string]string{"If-Match": newEtag})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+}
@@ -0,0 +1,75 @@
+package tests
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"EffectiveMobile/internal/models"
+)
+
+func TestTrash(t *testing.T) {
+	t.Log("Soft delete and restore")
+	// Создаем тестовые данные
+	testSong := models.SongRequest{
+		Name:   "Uprising",
+		Artist: "Muse",
+	}
+
+	statusCode, song, err := CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, err = DeleteSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// Удаленная песня не читается, но лежит в корзине
+	statusCode, _, err = GetSongByID(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusNotFound, statusCode)
+
+	var trash models.TrashResponse
+	statusCode, trash, err = GetTrash(t, url.Values{"limit": {"100"}})
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	found := false
+	for _, trashed := range trash.Songs {
+		if trashed.ID == song.ID {
+			found = true
+			assert.False(t, trashed.DeletedAt.IsZero())
+		}
+	}
+	assert.True(t, found)
+
+	// Название освобождается для новой песни, поэтому восстановить старую нельзя
+	statusCode, duplicate, err := CreateSong(t, testSong)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, statusCode)
+
+	statusCode, _, err = RestoreSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusConflict, statusCode)
+
+	statusCode, err = DeleteSong(t, duplicate)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	var restored models.Song
+	statusCode, restored, err = RestoreSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+	assert.Equal(t, song.ID, restored.ID)
+
+	statusCode, _, err = GetSongByID(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+
+	// Удаляем тестовые данные
+	statusCode, err = DeleteSong(t, song)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, statusCode)
+}